@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/render"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// checkOutputs compares the generated documentation with the files on disk, printing a unified diff
+// for each file that is missing or out of date, and for each leftover file that documents no module.
+func checkOutputs(outputs []*output, leftover []string, stdout, stderr io.Writer) int {
+	stale := 0
+
+	for _, o := range outputs {
+		current, err := ioutil.ReadFile(o.Path)
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitError
+		}
+		if bytes.Equal(current, o.Body) {
+			continue
+		}
+		stale++
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current)),
+			B:        difflib.SplitLines(string(o.Body)),
+			FromFile: o.Path,
+			ToFile:   o.Path + " (generated)",
+			Context:  3,
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitError
+		}
+		fmt.Fprint(stdout, diff)
+	}
+
+	for _, path := range leftover {
+		current, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitError
+		}
+		stale++
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current)),
+			FromFile: path,
+			ToFile:   path + " (no module)",
+			Context:  3,
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitError
+		}
+		fmt.Fprint(stdout, diff)
+		fmt.Fprintf(stderr, "tf_docs: %s documents no module, remove it\n", path)
+	}
+
+	if stale > 0 {
+		fmt.Fprintf(stderr, "tf_docs: %d of %d files are out of date, run tf_docs generate to update them\n", stale, len(outputs)+len(leftover))
+		return exitStale
+	}
+
+	return exitOK
+}
+
+// leftoverOutputs returns the files within the directory out that look generated per module by
+// renderer, with the extension ext, that none of the outputs is written to, such as the documentation
+// of a module that has been removed. A file looks generated when its first line is the first line
+// renderer writes for a module with the file's name as its link, so hand written files, such as an
+// index README.md, are left alone.
+func leftoverOutputs(out, ext string, renderer render.Renderer, outputs []*output) ([]string, error) {
+	infos, err := ioutil.ReadDir(out)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	generated := map[string]bool{}
+	for _, o := range outputs {
+		generated[o.Path] = true
+	}
+	var leftover []string
+	for _, info := range infos {
+		path := filepath.Join(out, info.Name())
+		if info.IsDir() || filepath.Ext(info.Name()) != ext || generated[path] {
+			continue
+		}
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		link := strings.TrimSuffix(info.Name(), ext)
+		var buf bytes.Buffer
+		if err := renderer.RenderModule(&buf, &tf_docs.TFModule{Title: link, Link: link}); err != nil {
+			continue
+		}
+		if firstLine(body) == firstLine(buf.Bytes()) {
+			leftover = append(leftover, path)
+		}
+	}
+	return leftover, nil
+}
+
+// firstLine returns the first line of body, without its line ending.
+func firstLine(body []byte) string {
+	line := strings.SplitN(string(body), "\n", 2)[0]
+	return strings.TrimSuffix(line, "\r")
+}
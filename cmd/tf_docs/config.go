@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/config"
+)
+
+// loadConfig loads the configuration file of the modules within directory and sets each of the named
+// flags it configures that was not given on the command line, so that flags override the file. A
+// -format flag also overrides the file's templates.
+func loadConfig(flags *flag.FlagSet, directory string, names ...string) (*config.Config, error) {
+	cfg, err := config.Load(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	given := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	values := cfg.Flags()
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok || given[name] || name == "template" && given["format"] {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", cfg.Path, name, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// parseOptions returns the options for finding and parsing modules written in syntax, hcl1 or hcl2,
+// following the configuration. Each module is sorted in the configured order.
+func parseOptions(cfg *config.Config, syntax string) (tf_docs.Options, error) {
+	opts := cfg.Options()
+
+	var parse tf_docs.ParseFunc
+	switch syntax {
+	case "hcl1":
+		parse = opts.Conventions.ParseFiles
+	case "hcl2":
+		parse = opts.Conventions.ParseHCL2Files
+	default:
+		return opts, fmt.Errorf("unknown syntax %q, expected hcl1 or hcl2", syntax)
+	}
+
+	opts.Parser = parse
+	if cfg.Sort == config.SortName {
+		opts.Parser = func(files []*tf_docs.File, moduleName string) (*tf_docs.TFModule, error) {
+			module, err := parse(files, moduleName)
+			if err != nil {
+				return module, err
+			}
+			tf_docs.SortByName(module)
+			return module, nil
+		}
+	}
+	return opts, nil
+}
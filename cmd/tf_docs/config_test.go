@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	root := t.TempDir()
+	vpc := filepath.Join(root, "modules", "vpc")
+	assert.NoError(t, os.MkdirAll(vpc, 0755), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(vpc, "main.tf"), []byte(`# Creates a network
+
+variable "zone" {}
+
+variable "cidr" {}
+
+resource "aws_vpc" "main" {
+  cidr_block = "${var.cidr}-${var.zone}"
+}
+`), 0644), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, config.FileName), []byte(`format: yaml
+sort: name
+sections: [description, variables]
+lint:
+  disable: [lead-comment]
+conventions:
+  description: first-comment
+`), 0644), "")
+	modules := filepath.Join(root, "modules")
+
+	cases := []struct {
+		Command func(args []string, stdout, stderr io.Writer) int
+		Args    []string
+		Exit    int
+		Stdout  string
+		Missing string
+	}{
+		{
+			Command: generate,
+			Args:    []string{modules},
+			Exit:    exitOK,
+			Stdout:  "description: Creates a network\n    variables:\n      - name: cidr\n",
+			Missing: "resources:",
+		},
+		{
+			Command: generate,
+			Args:    []string{"-format", "markdown", modules},
+			Exit:    exitOK,
+			Stdout:  "Creates a network\n",
+		},
+		{
+			Command: lintCommand,
+			Args:    []string{modules},
+			Exit:    exitFindings,
+			Missing: "lead-comment",
+		},
+		{
+			Command: lintCommand,
+			Args:    []string{"-disable", "", modules},
+			Exit:    exitFindings,
+			Stdout:  "resource aws_vpc.main has no lead comment (lead-comment)",
+		},
+		{
+			Command: graphCommand,
+			Args:    []string{modules},
+			Exit:    exitOK,
+			Stdout:  "digraph",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("config %v", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.Exit, c.Command(c.Args, &stdout, &stderr), stderr.String())
+			assert.Contains(t, stdout.String(), c.Stdout, "")
+			if c.Missing != "" {
+				assert.NotContains(t, stdout.String(), c.Missing, "")
+			}
+		})
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(vpc, config.FileName), []byte("formats: json\n"), 0644), "")
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, generate([]string{vpc}, &stdout, &stderr), "")
+	assert.True(t, strings.Contains(stderr.String(), "field formats not found"), stderr.String())
+}
+
+func TestParseOptions(t *testing.T) {
+	_, err := parseOptions(&config.Config{}, "hcl3")
+	assert.EqualError(t, err, `unknown syntax "hcl3", expected hcl1 or hcl2`, "")
+
+	opts, err := parseOptions(&config.Config{Sort: config.SortName, Sections: []string{"outputs"}}, "hcl2")
+	assert.NoError(t, err, "")
+	module, err := opts.Parser([]*tf_docs.File{{Name: "main.tf", Body: "variable \"cidr\" {}\n"}}, "empty")
+	assert.NoError(t, err, "")
+	assert.Equal(t, "empty", module.Title, "")
+	assert.Len(t, module.Variables, 1, "")
+}
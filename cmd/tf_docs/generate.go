@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/render"
+)
+
+// generate implements the generate command, writing documentation for every module within a
+// directory to stdout, a single file or one file per module, or injecting each module's documentation
+// into a file within the module's directory.
+func generate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "markdown", "output format, one of: "+strings.Join(render.Formats(), ", "))
+	templates := flags.String("template", "", "comma separated Go template files defining \"module\" and \"modules\", used instead of -format")
+	htmlTemplate := flags.Bool("template-html", false, "parse the -template files with html/template rather than text/template")
+	syntax := flags.String("syntax", "hcl2", "syntax of the Terraform files, hcl1 or hcl2")
+	out := flags.String("out", "", "file to write to, or the directory to write to with -per-module (default stdout)")
+	perModule := flags.Bool("per-module", false, "write one file per module, named after the module's link, into the -out directory")
+	concurrency := flags.Int("concurrency", 0, "maximum number of modules parsed at once (default GOMAXPROCS)")
+	continueOnError := flags.Bool("continue-on-error", false, "document the modules that can be parsed and report the others as warnings")
+	check := flags.Bool("check", false, "compare the documentation with the -out or -inject files rather than writing it, printing a diff of any that are out of date, or, with -per-module, that document no module")
+	inject := flags.String("inject", "", "name of a file within each module's directory, such as README.md, to inject the module's documentation into between the markers")
+	beginMarker := flags.String("begin-marker", render.DefaultBeginMarker, "marker starting the region -inject replaces")
+	endMarker := flags.String("end-marker", render.DefaultEndMarker, "marker ending the region -inject replaces")
+	watchFlag := flags.Bool("watch", false, "keep running, rewriting the -out or -inject files of the modules whose .tf files change; modules that cannot be parsed are reported as warnings")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tf_docs generate [flags] <directory>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	cfg, err := loadConfig(flags, flags.Arg(0), "format", "template")
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	if *perModule && *out == "" {
+		fmt.Fprintln(stderr, "tf_docs: -per-module requires an -out directory")
+		return exitUsage
+	}
+	if *inject != "" && *out != "" {
+		fmt.Fprintln(stderr, "tf_docs: -inject cannot be used with -out")
+		return exitUsage
+	}
+	if *check && *out == "" && *inject == "" {
+		fmt.Fprintln(stderr, "tf_docs: -check requires an -out file or directory, or -inject")
+		return exitUsage
+	}
+	if *watchFlag && (*check || *out == "" && *inject == "") {
+		fmt.Fprintln(stderr, "tf_docs: -watch requires an -out file or directory, or -inject, and cannot be used with -check")
+		return exitUsage
+	}
+
+	renderer, ext, err := render.ForFormat(*format)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	if paths := splitList(*templates); len(paths) > 0 {
+		newTemplate := render.NewTemplate
+		if *htmlTemplate {
+			newTemplate = render.NewHTMLTemplate
+		}
+		if renderer, err = newTemplate(paths...); err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitUsage
+		}
+		ext = templateExtension(paths[0])
+	}
+	if renderer, err = render.WithSections(renderer, cfg.Sections); err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	opts, err := parseOptions(cfg, *syntax)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	outputs := func(modules []*tf_docs.TFModule) ([]*output, error) {
+		if *inject != "" {
+			return injectOutputs(renderer, modules, *inject, render.Markers{Begin: *beginMarker, End: *endMarker})
+		}
+		return renderOutputs(renderer, modules, *out, *perModule, ext)
+	}
+	if *watchFlag {
+		if *perModule {
+			if err := os.MkdirAll(*out, 0755); err != nil {
+				fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+				return exitError
+			}
+		}
+		return watchOutputs(ctx, flags.Arg(0), opts, outputs, *perModule || *inject != "", stderr)
+	}
+
+	opts.ContinueOnError = *continueOnError
+	opts.Concurrency = *concurrency
+	result, err := tf_docs.FindAndParseContext(ctx, flags.Arg(0), opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitCode(err)
+	}
+	for _, failure := range result.Failures {
+		fmt.Fprintf(stderr, "tf_docs: warning: skipping module %s:\n%s\n", failure.Dir, failure.Err)
+	}
+	if err := allFailed(result); err != nil {
+		fmt.Fprintln(stderr, "tf_docs: no module could be parsed")
+		return exitCode(err)
+	}
+	modules := result.Modules
+
+	if *out == "" && *inject == "" {
+		if err := renderer.RenderModules(stdout, modules); err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	generated, err := outputs(modules)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+	if *check {
+		var leftover []string
+		if *perModule {
+			if leftover, err = leftoverOutputs(*out, ext, renderer, generated); err != nil {
+				fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+				return exitError
+			}
+		}
+		return checkOutputs(generated, leftover, stdout, stderr)
+	}
+	if *perModule {
+		if err := os.MkdirAll(*out, 0755); err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitError
+		}
+	}
+	for _, o := range generated {
+		if err := ioutil.WriteFile(o.Path, o.Body, 0644); err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitError
+		}
+	}
+
+	return exitOK
+}
+
+// templateExtension returns the extension of the files written using a template, taken from the name
+// of its first file without any template extension: docs.html.tmpl gives ".html". Without one, files
+// are written with ".txt".
+func templateExtension(path string) string {
+	name := filepath.Base(path)
+	for _, suffix := range []string{".tmpl", ".gotmpl", ".tpl"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	if ext := filepath.Ext(name); ext != "" {
+		return ext
+	}
+	return ".txt"
+}
+
+// output is the documentation generated for a file.
+type output struct {
+	Path string
+	Body []byte
+}
+
+// renderOutputs renders the documentation of modules in memory: to the single file out, or with
+// perModule to a file per module, named after the module's link, within the directory out.
+func renderOutputs(renderer render.Renderer, modules []*tf_docs.TFModule, out string, perModule bool, ext string) ([]*output, error) {
+	if !perModule {
+		var buf bytes.Buffer
+		if err := renderer.RenderModules(&buf, modules); err != nil {
+			return nil, err
+		}
+		return []*output{{Path: out, Body: buf.Bytes()}}, nil
+	}
+
+	var outputs []*output
+	for _, module := range modules {
+		var buf bytes.Buffer
+		if err := renderer.RenderModule(&buf, module); err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, &output{Path: filepath.Join(out, module.Link+ext), Body: buf.Bytes()})
+	}
+	return outputs, nil
+}
+
+// injectOutputs renders the documentation of each module in memory and injects it between the
+// markers of the file named name within the module's directory. The files must already exist.
+func injectOutputs(renderer render.Renderer, modules []*tf_docs.TFModule, name string, markers render.Markers) ([]*output, error) {
+	var outputs []*output
+	for _, module := range modules {
+		var buf bytes.Buffer
+		if err := renderer.RenderModule(&buf, module); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(module.Dir, name)
+		document, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		body, err := render.Inject(document, buf.Bytes(), markers)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		outputs = append(outputs, &output{Path: path, Body: body})
+	}
+	return outputs, nil
+}
+
+// writeFile creates or truncates the file at path and writes to it using write.
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	broken := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(broken, "main.tf"), []byte(`variable "x" {`), 0644), "")
+	mixed := t.TempDir()
+	for name, body := range map[string]string{"good": `variable "x" {}`, "broken": `variable "x" {`} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(mixed, name), 0755), "")
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(mixed, name, "main.tf"), []byte(body), 0644), "")
+	}
+
+	cases := []struct {
+		Args   []string
+		Exit   int
+		Stdout string
+	}{
+		{
+			Args:   []string{"../../testdata/modules/depth1"},
+			Exit:   exitOK,
+			Stdout: "<a name=\"depth1\"></a>\n## depth1\n",
+		},
+		{
+			Args:   []string{"-syntax", "hcl1", "-format", "md", "../../testdata/modules/depth2"},
+			Exit:   exitOK,
+			Stdout: "- [module2](#module2)\n",
+		},
+		{
+			Args:   []string{"-format", "yaml", "../../testdata/modules/depth2"},
+			Exit:   exitOK,
+			Stdout: "schema_version: \"1\"\n",
+		},
+		{
+			Args:   []string{"-format", "toml", "../../testdata/modules/depth2"},
+			Exit:   exitOK,
+			Stdout: "schema_version = \"1\"\n",
+		},
+		{
+			Args: []string{"../../testdata/modules/none"},
+			Exit: exitNoModules,
+		},
+		{
+			Args: []string{broken},
+			Exit: exitParse,
+		},
+		{
+			Args: []string{""},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"-continue-on-error", broken},
+			Exit: exitParse,
+		},
+		{
+			Args:   []string{"-continue-on-error", mixed},
+			Exit:   exitOK,
+			Stdout: "## good\n",
+		},
+		{
+			Args: []string{"../../testdata/modules/missing"},
+			Exit: exitError,
+		},
+		{
+			Args: []string{"-format", "docx", "../../testdata/modules/depth1"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"-per-module", "../../testdata/modules/depth1"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"-watch", "../../testdata/modules/depth1"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"-watch", "-check", "-out", "docs.md", "../../testdata/modules/depth1"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{},
+			Exit: exitUsage,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("generate %v", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.Exit, generate(c.Args, &stdout, &stderr), stderr.String())
+			assert.Contains(t, stdout.String(), c.Stdout, "")
+		})
+	}
+}
+
+func TestGenerateOutput(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	file := filepath.Join(dir, "docs.md")
+	exit := generate([]string{"-out", file, "../../testdata/modules/depth2"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, exit, stderr.String())
+	body, err := ioutil.ReadFile(file)
+	assert.NoError(t, err, "")
+	assert.Contains(t, string(body), "# Modules\n", "")
+
+	perModule := filepath.Join(dir, "modules")
+	exit = generate([]string{"-per-module", "-out", perModule, "../../testdata/modules/depth2"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, exit, stderr.String())
+	for _, name := range []string{"module1", "module2"} {
+		body, err := ioutil.ReadFile(filepath.Join(perModule, name+".md"))
+		assert.NoError(t, err, "")
+		assert.Contains(t, string(body), "# "+name+"\n", "")
+	}
+	assert.Empty(t, stdout.String(), "")
+}
+
+func TestGenerateCheck(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "docs.md")
+	perModule := filepath.Join(dir, "modules")
+
+	var stdout, stderr bytes.Buffer
+	exit := generate([]string{"-check", "-out", file, "../../testdata/modules/depth2"}, &stdout, &stderr)
+	assert.Equal(t, exitStale, exit, stderr.String())
+	assert.Contains(t, stdout.String(), "+++ "+file+" (generated)\n", "")
+
+	for _, args := range [][]string{{"-out", file}, {"-per-module", "-out", perModule}} {
+		stdout.Reset()
+		args = append(args, "../../testdata/modules/depth2")
+		assert.Equal(t, exitOK, generate(args, &stdout, &stderr), stderr.String())
+		assert.Equal(t, exitOK, generate(append([]string{"-check"}, args...), &stdout, &stderr), stderr.String())
+		assert.Empty(t, stdout.String(), "")
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(perModule, "module1.md"), []byte("# module1\nstale\n"), 0644), "")
+	exit = generate([]string{"-check", "-per-module", "-out", perModule, "../../testdata/modules/depth2"}, &stdout, &stderr)
+	assert.Equal(t, exitStale, exit, stderr.String())
+	assert.Contains(t, stdout.String(), "-stale\n", "")
+	assert.NotContains(t, stdout.String(), "module2.md", "")
+
+	removed := filepath.Join(perModule, "removed.md")
+	exit = generate([]string{"-per-module", "-out", perModule, "../../testdata/modules/depth2"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, exit, stderr.String())
+	assert.NoError(t, ioutil.WriteFile(removed, []byte("<a name=\"removed\"></a>\n# removed\n"), 0644), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(perModule, "README.md"), []byte("# Modules\n"), 0644), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(perModule, "notes.txt"), []byte("kept\n"), 0644), "")
+	stdout.Reset()
+	stderr.Reset()
+	exit = generate([]string{"-check", "-per-module", "-out", perModule, "../../testdata/modules/depth2"}, &stdout, &stderr)
+	assert.Equal(t, exitStale, exit, stderr.String())
+	assert.Contains(t, stdout.String(), "+++ "+removed+" (no module)\n", "")
+	assert.Contains(t, stdout.String(), "-# removed\n", "")
+	assert.Contains(t, stderr.String(), removed+" documents no module", "")
+	assert.NotContains(t, stdout.String(), "notes.txt", "")
+	assert.NotContains(t, stdout.String(), "README.md", "")
+	assert.NotContains(t, stdout.String(), "module1.md", "")
+
+	exit = generate([]string{"-check", "../../testdata/modules/depth2"}, &stdout, &stderr)
+	assert.Equal(t, exitUsage, exit, "")
+}
+
+func TestGenerateInject(t *testing.T) {
+	dir := t.TempDir()
+	module := filepath.Join(dir, "vpc")
+	assert.NoError(t, os.MkdirAll(module, 0755), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(module, "main.tf"), []byte("# vpc creates a network\n"), 0644), "")
+	readme := filepath.Join(module, "README.md")
+
+	var stdout, stderr bytes.Buffer
+	exit := generate([]string{"-inject", "README.md", dir}, &stdout, &stderr)
+	assert.Equal(t, exitError, exit, "a missing README should fail")
+
+	assert.NoError(t, ioutil.WriteFile(readme, []byte("hand written\n"), 0644), "")
+	stderr.Reset()
+	exit = generate([]string{"-inject", "README.md", dir}, &stdout, &stderr)
+	assert.Equal(t, exitError, exit, "")
+	assert.Contains(t, stderr.String(), "markers not found", "")
+
+	assert.NoError(t, ioutil.WriteFile(readme, []byte("hand written\n<!-- BEGIN_TF_DOCS -->\n<!-- END_TF_DOCS -->\nfooter\n"), 0644), "")
+	exit = generate([]string{"-check", "-inject", "README.md", dir}, &stdout, &stderr)
+	assert.Equal(t, exitStale, exit, stderr.String())
+	for i := 0; i < 2; i++ {
+		exit = generate([]string{"-inject", "README.md", dir}, &stdout, &stderr)
+		assert.Equal(t, exitOK, exit, stderr.String())
+	}
+	body, err := ioutil.ReadFile(readme)
+	assert.NoError(t, err, "")
+	assert.Equal(t, "hand written\n<!-- BEGIN_TF_DOCS -->\n<a name=\"vpc\"></a>\n# vpc\n\nvpc creates a network\n<!-- END_TF_DOCS -->\nfooter\n", string(body), "")
+
+	stdout.Reset()
+	exit = generate([]string{"-check", "-inject", "README.md", dir}, &stdout, &stderr)
+	assert.Equal(t, exitOK, exit, stderr.String())
+	assert.Empty(t, stdout.String(), "")
+
+	exit = generate([]string{"-inject", "README.md", "-out", readme, dir}, &stdout, &stderr)
+	assert.Equal(t, exitUsage, exit, "")
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	exit := generate([]string{"-template", "../../testdata/templates/list.md.tmpl", "-per-module", "-out", dir, "../../testdata/modules/calls"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, exit, stderr.String())
+	body, err := ioutil.ReadFile(filepath.Join(dir, "vpc.md"))
+	assert.NoError(t, err, "")
+	assert.Equal(t, "# vpc\n\n- cidr (required)\n- name\n", string(body), "")
+
+	exit = generate([]string{"-template", "../../testdata/templates/missing.tmpl", "../../testdata/modules/calls"}, &stdout, &stderr)
+	assert.Equal(t, exitUsage, exit, "")
+}
+
+func TestTemplateExtension(t *testing.T) {
+	assert.Equal(t, ".html", templateExtension("templates/docs.html.tmpl"), "")
+	assert.Equal(t, ".md", templateExtension("docs.md"), "")
+	assert.Equal(t, ".txt", templateExtension("docs.tmpl"), "")
+}
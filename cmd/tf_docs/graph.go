@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/graph"
+)
+
+// graphWriters maps the names accepted by the -format flag of the graph command to the method
+// writing a graph in that format.
+var graphWriters = map[string]func(*graph.Graph, io.Writer) error{
+	"dot":     (*graph.Graph).WriteDOT,
+	"mermaid": (*graph.Graph).WriteMermaid,
+}
+
+// graphCommand implements the graph command, writing the dependency graph of the modules within a
+// directory, or of a single module, or the reference graph of a single module.
+func graphCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "dot", "graph format, dot or mermaid")
+	syntax := flags.String("syntax", "hcl2", "syntax of the Terraform files, hcl1 or hcl2")
+	module := flags.String("module", "", "link of a single module to graph, with the modules it depends on")
+	references := flags.Bool("references", false, "write the references between the objects of the -module rather than its dependencies")
+	out := flags.String("out", "", "file to write to (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tf_docs graph [flags] <directory>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	cfg, err := loadConfig(flags, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	write, ok := graphWriters[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "tf_docs: unknown graph format %q, expected dot or mermaid\n", *format)
+		return exitUsage
+	}
+	if *references && *module == "" {
+		fmt.Fprintln(stderr, "tf_docs: -references requires a -module")
+		return exitUsage
+	}
+	opts, err := parseOptions(cfg, *syntax)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+
+	result, err := tf_docs.FindAndParseWithOptions(flags.Arg(0), opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitCode(err)
+	}
+
+	g := graph.Modules(result.Modules)
+	if *module != "" {
+		var found *tf_docs.TFModule
+		for _, m := range result.Modules {
+			if m.Link == *module {
+				found = m
+			}
+		}
+		if found == nil {
+			fmt.Fprintf(stderr, "tf_docs: no module with link %q\n", *module)
+			return exitUsage
+		}
+		g = graph.Module(result.Modules, found)
+		if *references {
+			g = graph.References(found)
+		}
+	}
+
+	if *out != "" {
+		err = writeFile(*out, func(w io.Writer) error { return write(g, w) })
+	} else {
+		err = write(g, stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
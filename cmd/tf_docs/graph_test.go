@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphCommand(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Exit   int
+		Stdout string
+	}{
+		{
+			Args:   []string{"../../testdata/modules/calls"},
+			Exit:   exitOK,
+			Stdout: `"app" -> "vpc" [label="vpc"];`,
+		},
+		{
+			Args:   []string{"-format", "mermaid", "-module", "vpc", "../../testdata/modules/calls"},
+			Exit:   exitOK,
+			Stdout: "graph LR\n  n0[\"vpc\"]\n",
+		},
+		{
+			Args:   []string{"-references", "-module", "app", "../../testdata/modules/calls"},
+			Exit:   exitOK,
+			Stdout: `"output.vpc_id" -> "module.vpc";`,
+		},
+		{
+			Args: []string{"-references", "../../testdata/modules/calls"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"-module", "missing", "../../testdata/modules/calls"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"-format", "svg", "../../testdata/modules/calls"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"../../testdata/modules/none"},
+			Exit: exitNoModules,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("graph %v", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.Exit, graphCommand(c.Args, &stdout, &stderr), stderr.String())
+			assert.Contains(t, stdout.String(), c.Stdout, "")
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/lint"
+)
+
+// lintCommand implements the lint command, checking the documentation of every module within a
+// directory against the lint rules. Only issues with error severity fail the command.
+func lintCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	syntax := flags.String("syntax", "hcl2", "syntax of the Terraform files, hcl1 or hcl2")
+	disable := flags.String("disable", "", "comma separated IDs of rules to disable")
+	severities := flags.String("severity", "", "comma separated rule=severity pairs overriding the severity of rules")
+	list := flags.Bool("rules", false, "list the rules and exit")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tf_docs lint [flags] <directory>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if *list {
+		for _, rule := range lint.Rules() {
+			fmt.Fprintf(stdout, "%-22s %-8s %s\n", rule.ID, rule.Severity, rule.Description)
+		}
+		return exitOK
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	cfg, err := loadConfig(flags, flags.Arg(0), "disable", "severity")
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	opts, err := parseOptions(cfg, *syntax)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+
+	config, err := lintConfig(*disable, *severities)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	linter, err := lint.NewForConventions(opts.Conventions, config)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+
+	result, err := tf_docs.FindAndParseWithOptions(flags.Arg(0), opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitCode(err)
+	}
+
+	failed := false
+	for _, issue := range linter.Lint(result.Modules) {
+		fmt.Fprintln(stdout, issue)
+		if issue.Severity == lint.Error {
+			failed = true
+		}
+	}
+	if failed {
+		return exitFindings
+	}
+
+	return exitOK
+}
+
+// lintConfig builds the configuration of the lint rules from the -disable and -severity flags.
+func lintConfig(disable, severities string) (lint.Config, error) {
+	config := lint.Config{}
+
+	for _, id := range splitList(disable) {
+		c := config[id]
+		c.Disabled = true
+		config[id] = c
+	}
+	for _, pair := range splitList(severities) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid -severity %q, expected rule=severity", pair)
+		}
+		c := config[parts[0]]
+		c.Severity = lint.Severity(parts[1])
+		config[parts[0]] = c
+	}
+
+	return config, nil
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nathmclean/tf_docs/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintCommand(t *testing.T) {
+	firstComment := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(firstComment, config.FileName), []byte("conventions:\n  description: first-comment\n"), 0644), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(firstComment, "main.tf"), []byte("variable \"x\" {\n  description = \"x\"\n}\n"), 0644), "")
+
+	cases := []struct {
+		Args   []string
+		Exit   int
+		Stdout string
+	}{
+		{
+			Args:   []string{"../../testdata/modules/calls"},
+			Exit:   exitOK,
+			Stdout: `warning: ../../testdata/modules/calls/vpc/main.tf:14:1: resource aws_vpc.main has no lead comment (lead-comment)`,
+		},
+		{
+			Args:   []string{"-severity", "lead-comment=error", "../../testdata/modules/calls"},
+			Exit:   exitFindings,
+			Stdout: `error: ../../testdata/modules/calls/vpc/main.tf:14:1: resource aws_vpc.main has no lead comment (lead-comment)`,
+		},
+		{
+			Args: []string{"-disable", "lead-comment", "-severity", "module-description=error", "../../testdata/modules/calls"},
+			Exit: exitOK,
+		},
+		{
+			Args:   []string{"-severity", "module-description=error", firstComment},
+			Exit:   exitFindings,
+			Stdout: "module has no description, start a file with a comment (module-description)",
+		},
+		{
+			Args:   []string{"-rules"},
+			Exit:   exitOK,
+			Stdout: "snake-case",
+		},
+		{
+			Args: []string{"-disable", "missing", "../../testdata/modules/calls"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"-severity", "snake-case", "../../testdata/modules/calls"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"../../testdata/modules/none"},
+			Exit: exitNoModules,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("lint %v", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.Exit, lintCommand(c.Args, &stdout, &stderr), stderr.String())
+			assert.Contains(t, stdout.String(), c.Stdout, "")
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Command tf_docs generates documentation for the Terraform modules found within a directory.
+//
+// Usage:
+//
+//	tf_docs generate [flags] <directory>
+//	tf_docs validate [flags] <directory>
+//	tf_docs graph [flags] <directory>
+//	tf_docs lint [flags] <directory>
+//	tf_docs site [flags] -out <site directory> <directory>
+//	tf_docs serve [flags] <directory>
+//
+// Every command reads the .tf_docs.yml configuration file found in the directory, or the nearest of
+// its parents. Flags given on the command line override the file.
+//
+// Exit codes:
+//
+//	0 success
+//	1 an unexpected error, such as a directory or file that cannot be read or written
+//	2 invalid usage
+//	3 a module could not be parsed
+//	4 no modules were found
+//	5 problems were found in the modules
+//	6 generated documentation is out of date (generate -check)
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+const (
+	exitOK        = 0
+	exitError     = 1
+	exitUsage     = 2
+	exitParse     = 3
+	exitNoModules = 4
+	exitFindings  = 5
+	exitStale     = 6
+)
+
+const usageText = `Usage: tf_docs <command> [flags] <directory>
+
+Commands:
+  generate  generate documentation for every module within a directory
+  validate  check calls to local modules and the use of variables
+  graph     write the module dependency graph as Graphviz DOT or Mermaid
+  lint      check the documentation of every module against the lint rules
+  site      write a static HTML documentation site
+  serve     serve the HTML documentation, reloading it as modules change
+
+Configuration is read from the nearest .tf_docs.yml in or above <directory>.
+Run "tf_docs <command> -h" for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command named by the first argument and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usageText)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "generate":
+		return generate(args[1:], stdout, stderr)
+	case "validate":
+		return validate(args[1:], stdout, stderr)
+	case "graph":
+		return graphCommand(args[1:], stdout, stderr)
+	case "lint":
+		return lintCommand(args[1:], stdout, stderr)
+	case "site":
+		return siteCommand(args[1:], stdout, stderr)
+	case "serve":
+		return serveCommand(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usageText)
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "tf_docs: unknown command %q\n\n%s", args[0], usageText)
+	return exitUsage
+}
+
+// allFailed returns the errors of the modules that were skipped when every module was, so that there
+// is nothing to document, and nil otherwise.
+func allFailed(result *tf_docs.Result) error {
+	if len(result.Modules) > 0 || len(result.Failures) == 0 {
+		return nil
+	}
+	var errs tf_docs.Errors
+	for _, failure := range result.Failures {
+		errs = append(errs, failure.Err)
+	}
+	return errs
+}
+
+// exitCode maps an error returned while finding and parsing modules to an exit code.
+func exitCode(err error) int {
+	var syntaxErr *tf_docs.SyntaxError
+	var schemaErr *tf_docs.SchemaError
+	switch {
+	case errors.Is(err, tf_docs.ErrNoModules):
+		return exitNoModules
+	case errors.Is(err, tf_docs.ErrEmptyDirectory):
+		return exitUsage
+	case errors.As(err, &syntaxErr), errors.As(err, &schemaErr):
+		return exitParse
+	}
+	return exitError
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		Args []string
+		Exit int
+	}{
+		{
+			Args: []string{},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{"help"},
+			Exit: exitOK,
+		},
+		{
+			Args: []string{"unknown"},
+			Exit: exitUsage,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("run %v", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.Exit, run(c.Args, &stdout, &stderr), stderr.String())
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		Err  error
+		Exit int
+	}{
+		{
+			Err:  fmt.Errorf("%w in path dir", tf_docs.ErrNoModules),
+			Exit: exitNoModules,
+		},
+		{
+			Err:  tf_docs.ErrEmptyDirectory,
+			Exit: exitUsage,
+		},
+		{
+			Err:  &os.PathError{Op: "open", Path: "dir", Err: os.ErrNotExist},
+			Exit: exitError,
+		},
+		{
+			Err:  tf_docs.Errors{&tf_docs.SchemaError{BlockKind: "variable", Message: "type is required for a variable"}},
+			Exit: exitParse,
+		},
+		{
+			Err:  &tf_docs.SyntaxError{Filename: "main.tf", Line: 1, Message: "unexpected EOF"},
+			Exit: exitParse,
+		},
+		{
+			Err:  fmt.Errorf("something else"),
+			Exit: exitError,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("exitCode %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Exit, exitCode(c.Err), "")
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/serve"
+	"github.com/nathmclean/tf_docs/watch"
+)
+
+// serveCommand implements the serve command, serving the HTML documentation of every module within a
+// directory until interrupted. Modules are parsed again as their .tf files change and open pages
+// reload themselves.
+func serveCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	syntax := flags.String("syntax", "hcl2", "syntax of the Terraform files, hcl1 or hcl2")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tf_docs serve [flags] <directory>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	cfg, err := loadConfig(flags, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	opts, err := parseOptions(cfg, *syntax)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	directory := flags.Arg(0)
+
+	modules, err := watch.Load(directory, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitCode(err)
+	}
+	for _, failure := range modules.Failures() {
+		fmt.Fprintf(stderr, "tf_docs: warning: module %s:\n%s\n", failure.Dir, failure.Err)
+	}
+	server, err := serve.New(modules, cfg.Sections)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+	watcher, err := watch.New(directory, opts.Conventions, watch.DefaultDelay)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+	defer watcher.Close()
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	httpServer := &http.Server{Handler: server}
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- watcher.Run(ctx, func(dirs []string) {
+			updated, err := server.Update(dirs)
+			if err != nil {
+				fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+				return
+			}
+			reportUpdated(stderr, updated, server.Failures())
+		}, func(err error) {
+			fmt.Fprintf(stderr, "tf_docs: warning: %s\n", err)
+		})
+		stop()
+	}()
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	fmt.Fprintf(stdout, "Serving %s at http://%s/\n", directory, listener.Addr())
+	if err := httpServer.Serve(listener); err != http.ErrServerClosed {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+	if err := <-watchErr; err != nil && err != context.Canceled {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// reportUpdated reports the module directories that were parsed again or removed, along with the
+// errors of those that failed to parse.
+func reportUpdated(w io.Writer, updated []string, failures []*tf_docs.ModuleFailure) {
+	failed := map[string]error{}
+	for _, failure := range failures {
+		failed[filepath.Clean(failure.Dir)] = failure.Err
+	}
+	for _, dir := range updated {
+		if err, ok := failed[dir]; ok {
+			fmt.Fprintf(w, "tf_docs: warning: module %s:\n%s\n", dir, err)
+			continue
+		}
+		fmt.Fprintf(w, "tf_docs: updated %s\n", dir)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeCommand(t *testing.T) {
+	cases := []struct {
+		Args []string
+		Exit int
+	}{
+		{
+			Args: []string{"../../testdata/modules/none"},
+			Exit: exitNoModules,
+		},
+		{
+			Args: []string{"../../testdata/modules/missing"},
+			Exit: exitError,
+		},
+		{
+			Args: []string{"-addr", "localhost:-1", "../../testdata/modules/depth1"},
+			Exit: exitError,
+		},
+		{
+			Args: []string{"-syntax", "hcl3", "../../testdata/modules/depth1"},
+			Exit: exitUsage,
+		},
+		{
+			Args: []string{},
+			Exit: exitUsage,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("serve %v", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.Exit, serveCommand(c.Args, &stdout, &stderr), stderr.String())
+		})
+	}
+}
+
+func TestReportUpdated(t *testing.T) {
+	var buf bytes.Buffer
+	reportUpdated(&buf, []string{"modules/app", "modules/vpc"}, []*tf_docs.ModuleFailure{{Dir: "./modules/vpc", Err: errors.New("main.tf:1,15: invalid")}})
+	assert.Equal(t, "tf_docs: updated modules/app\ntf_docs: warning: module modules/vpc:\nmain.tf:1,15: invalid\n", buf.String(), "")
+}
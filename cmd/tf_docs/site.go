@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/render"
+	"github.com/nathmclean/tf_docs/site"
+)
+
+// siteCommand implements the site command, writing a static HTML documentation site for every
+// module within a directory.
+func siteCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("site", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	syntax := flags.String("syntax", "hcl2", "syntax of the Terraform files, hcl1 or hcl2")
+	out := flags.String("out", "", "directory to write the site to")
+	continueOnError := flags.Bool("continue-on-error", false, "document the modules that can be parsed and report the others as warnings")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tf_docs site [flags] -out <site directory> <directory>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() != 1 || *out == "" {
+		flags.Usage()
+		return exitUsage
+	}
+	cfg, err := loadConfig(flags, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	opts, err := parseOptions(cfg, *syntax)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+
+	opts.ContinueOnError = *continueOnError
+	result, err := tf_docs.FindAndParseWithOptions(flags.Arg(0), opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitCode(err)
+	}
+	for _, failure := range result.Failures {
+		fmt.Fprintf(stderr, "tf_docs: warning: skipping module %s:\n%s\n", failure.Dir, failure.Err)
+	}
+	if err := allFailed(result); err != nil {
+		fmt.Fprintln(stderr, "tf_docs: no module could be parsed")
+		return exitCode(err)
+	}
+
+	modules := result.Modules
+	if len(cfg.Sections) > 0 {
+		only, err := render.OnlySections(cfg.Sections)
+		if err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return exitUsage
+		}
+		modules = make([]*tf_docs.TFModule, len(result.Modules))
+		for i, module := range result.Modules {
+			modules[i] = only(module)
+		}
+	}
+
+	s, err := site.New(modules)
+	if err == nil {
+		err = s.Write(*out)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiteCommand(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	exit := siteCommand([]string{"-out", dir, "../../testdata/modules/calls"}, &stdout, &stderr)
+	assert.Equal(t, exitOK, exit, stderr.String())
+	for _, name := range []string{"index.html", "app.html", "vpc.html", "style.css"} {
+		_, err := ioutil.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err, "")
+	}
+	app, err := ioutil.ReadFile(filepath.Join(dir, "app.html"))
+	assert.NoError(t, err, "")
+	assert.Contains(t, string(app), `<a href="vpc.html">../vpc</a>`, "")
+
+	assert.Equal(t, exitUsage, siteCommand([]string{"../../testdata/modules/calls"}, &stdout, &stderr), "")
+	assert.Equal(t, exitNoModules, siteCommand([]string{"-out", dir, "../../testdata/modules/none"}, &stdout, &stderr), "")
+
+	broken := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(broken, "main.tf"), []byte(`variable "x" {`), 0644), "")
+	empty := t.TempDir()
+	stderr.Reset()
+	assert.Equal(t, exitParse, siteCommand([]string{"-continue-on-error", "-out", empty, broken}, &stdout, &stderr), "")
+	assert.Contains(t, stderr.String(), "no module could be parsed", "")
+	files, err := ioutil.ReadDir(empty)
+	assert.NoError(t, err, "")
+	assert.Empty(t, files, "")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// validate implements the validate command, checking every call to a local module against the
+// variables and outputs of the module it calls, and the use of variables within each module.
+func validate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	syntax := flags.String("syntax", "hcl2", "syntax of the Terraform files, hcl1 or hcl2")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tf_docs validate [flags] <directory>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	cfg, err := loadConfig(flags, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+	opts, err := parseOptions(cfg, *syntax)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitUsage
+	}
+
+	result, err := tf_docs.FindAndParseWithOptions(flags.Arg(0), opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitCode(err)
+	}
+
+	findings := tf_docs.ValidateModuleCalls(result.Modules)
+	findings = append(findings, tf_docs.ValidateVariables(result.Modules)...)
+	for _, finding := range findings {
+		fmt.Fprintln(stdout, finding)
+	}
+	if len(findings) > 0 {
+		return exitFindings
+	}
+
+	return exitOK
+}
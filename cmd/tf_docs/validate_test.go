@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nathmclean/tf_docs/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	blocks := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(blocks, config.FileName), []byte("conventions:\n  blocks: [variable, output]\n"), 0644), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(blocks, "main.tf"), []byte(`variable "cidr" {}
+
+variable "name" {}
+
+locals {
+  tags = { Name = var.name }
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = var.cidr
+  tags       = local.tags
+}
+
+output "id" {
+  value = aws_vpc.main.id
+}
+`), 0644), "")
+
+	cases := []struct {
+		Args   []string
+		Exit   int
+		Stdout string
+	}{
+		{
+			Args:   []string{"../../testdata/modules/calls"},
+			Exit:   exitFindings,
+			Stdout: `requires variable "cidr"`,
+		},
+		{
+			Args:   []string{"../../testdata/modules/variables"},
+			Exit:   exitFindings,
+			Stdout: `reference to undeclared variable "suffix"`,
+		},
+		{
+			Args: []string{"../../testdata/modules/calls/vpc"},
+			Exit: exitOK,
+		},
+		{
+			Args: []string{"../../testdata/modules/defaults"},
+			Exit: exitOK,
+		},
+		{
+			Args: []string{blocks},
+			Exit: exitOK,
+		},
+		{
+			Args: []string{"../../testdata/modules/none"},
+			Exit: exitNoModules,
+		},
+		{
+			Args: []string{},
+			Exit: exitUsage,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("validate %v", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.Exit, validate(c.Args, &stdout, &stderr), stderr.String())
+			assert.Contains(t, stdout.String(), c.Stdout, "")
+		})
+	}
+}
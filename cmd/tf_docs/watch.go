@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/watch"
+)
+
+// watchOutputs writes the outputs of every module within directory, found and parsed using opts,
+// then keeps them up to date as the modules' .tf files change until ctx is cancelled. When perModule
+// is set each module has its own outputs and only those of the modules that changed are rendered
+// again, otherwise the outputs of all of the modules are. Outputs that have not changed are not
+// rewritten.
+func watchOutputs(ctx context.Context, directory string, opts tf_docs.Options, outputs func([]*tf_docs.TFModule) ([]*output, error), perModule bool, stderr io.Writer) int {
+	modules, err := watch.Load(directory, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitCode(err)
+	}
+	for _, failure := range modules.Failures() {
+		fmt.Fprintf(stderr, "tf_docs: warning: module %s:\n%s\n", failure.Dir, failure.Err)
+	}
+	watcher, err := watch.New(directory, opts.Conventions, watch.DefaultDelay)
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+	defer watcher.Close()
+
+	generated, err := outputs(modules.Modules())
+	if err == nil {
+		err = writeChanged(generated, stderr)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+
+	err = watcher.Run(ctx, func(dirs []string) {
+		updated, err := modules.Update(dirs)
+		if err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+			return
+		}
+		if len(updated) == 0 {
+			return
+		}
+		reportUpdated(stderr, updated, modules.Failures())
+
+		affected := modules.Modules()
+		if perModule {
+			affected = updatedModules(affected, updated)
+		}
+		generated, err := outputs(affected)
+		if err == nil {
+			err = writeChanged(generated, stderr)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		}
+	}, func(err error) {
+		fmt.Fprintf(stderr, "tf_docs: warning: %s\n", err)
+	})
+	if err != nil && err != context.Canceled {
+		fmt.Fprintf(stderr, "tf_docs: %s\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// updatedModules returns the modules whose directories are among the cleaned directories updated.
+func updatedModules(modules []*tf_docs.TFModule, updated []string) []*tf_docs.TFModule {
+	isUpdated := map[string]bool{}
+	for _, dir := range updated {
+		isUpdated[dir] = true
+	}
+	var affected []*tf_docs.TFModule
+	for _, module := range modules {
+		if isUpdated[filepath.Clean(module.Dir)] {
+			affected = append(affected, module)
+		}
+	}
+	return affected
+}
+
+// writeChanged writes the outputs whose files do not already hold their body, reporting each file
+// written.
+func writeChanged(outputs []*output, w io.Writer) error {
+	for _, o := range outputs {
+		if current, err := ioutil.ReadFile(o.Path); err == nil && bytes.Equal(current, o.Body) {
+			continue
+		}
+		if err := ioutil.WriteFile(o.Path, o.Body, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "tf_docs: wrote %s\n", o.Path)
+	}
+	return nil
+}
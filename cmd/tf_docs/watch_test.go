@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/render"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventually waits for the file at path to contain want.
+func eventually(t *testing.T, path, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		body, err := ioutil.ReadFile(path)
+		if err == nil && bytes.Contains(body, []byte(want)) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s does not contain %q: %s", path, want, body)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWatchOutputs(t *testing.T) {
+	root := t.TempDir()
+	out := filepath.Join(t.TempDir(), "docs")
+	assert.NoError(t, os.MkdirAll(out, 0755), "")
+	write := func(dir, body string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755), "")
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(root, dir, "main.tf"), []byte(body), 0644), "")
+	}
+	write("app", "# app runs the application\n")
+	write("vpc", "# vpc creates a network\n")
+
+	renderer := render.NewMarkdown()
+	outputs := func(modules []*tf_docs.TFModule) ([]*output, error) {
+		return renderOutputs(renderer, modules, out, true, ".md")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	var stderr bytes.Buffer
+	go func() {
+		done <- watchOutputs(ctx, root, tf_docs.Options{Parser: tf_docs.ParseHCL2Files}, outputs, true, &stderr)
+	}()
+
+	app, vpc := filepath.Join(out, "app.md"), filepath.Join(out, "vpc.md")
+	eventually(t, app, "app runs the application")
+	eventually(t, vpc, "vpc creates a network")
+	before, err := os.Stat(app)
+	assert.NoError(t, err, "")
+
+	write("vpc", "# vpc creates a VPC\n")
+	eventually(t, vpc, "vpc creates a VPC")
+	write("network/dns", "# dns creates zones\n")
+	eventually(t, filepath.Join(out, "network_dns.md"), "dns creates zones")
+
+	after, err := os.Stat(app)
+	assert.NoError(t, err, "")
+	assert.Equal(t, before.ModTime(), after.ModTime(), "the documentation of modules that did not change should not be rewritten")
+
+	cancel()
+	assert.Equal(t, exitOK, <-done, stderr.String())
+	assert.Contains(t, stderr.String(), "tf_docs: updated "+filepath.Join(root, "vpc")+"\ntf_docs: wrote "+vpc+"\n", "")
+}
+
+func TestWriteChanged(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")
+	assert.NoError(t, ioutil.WriteFile(a, []byte("a\n"), 0644), "")
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeChanged([]*output{{Path: a, Body: []byte("a\n")}, {Path: b, Body: []byte("b\n")}}, &buf), "")
+	assert.Equal(t, "tf_docs: wrote "+b+"\n", buf.String(), "")
+	assert.Error(t, writeChanged([]*output{{Path: filepath.Join(dir, "missing", "c.md")}}, &buf), "")
+}
@@ -0,0 +1,215 @@
+// Package config reads the configuration file of a project, .tf_docs.yml, which sets how tf_docs
+// finds, parses and renders its modules. The file is found by searching from the directory of the
+// modules up through its parent directories.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/lint"
+	"github.com/nathmclean/tf_docs/render"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file.
+const FileName = ".tf_docs.yml"
+
+const (
+	// SortPosition leaves the elements of each module in the order they are declared.
+	SortPosition = "position"
+	// SortName sorts the elements of each module by name, as tf_docs.SortByName does.
+	SortName = "name"
+)
+
+// Config is the configuration of a project. Its zero value is the configuration used when a project
+// has no configuration file.
+type Config struct {
+	// Format is the output format of generate, one of render.Formats.
+	Format string `yaml:"format"`
+	// Templates are the Go template files generate renders with instead of Format. Read resolves them
+	// relative to the directory of the configuration file.
+	Templates []string `yaml:"templates"`
+	// Include and Exclude are the patterns choosing which module directories are documented, as
+	// tf_docs.Options.Include and tf_docs.Options.Exclude.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Sort is the order of the elements of each module, SortPosition or SortName. Defaults to
+	// SortPosition.
+	Sort string `yaml:"sort"`
+	// Sections are the sections of each module's documentation that are rendered, among
+	// render.Sections. Defaults to all of them.
+	Sections []string `yaml:"sections"`
+	// Lint configures the lint rules.
+	Lint Lint `yaml:"lint"`
+	// Conventions are the conventions the project's Terraform files follow.
+	Conventions Conventions `yaml:"conventions"`
+
+	// Path is the file the configuration was read from, empty if the project has none.
+	Path string `yaml:"-"`
+}
+
+// Lint configures the lint rules, as the lint command's -disable and -severity flags do.
+type Lint struct {
+	// Disable are the IDs of the rules that are not checked.
+	Disable []string `yaml:"disable"`
+	// Severity overrides the severity of rules, by ID.
+	Severity map[string]lint.Severity `yaml:"severity"`
+}
+
+// Conventions are the conventions the project's Terraform files follow, as tf_docs.Conventions.
+type Conventions struct {
+	Extensions  []string `yaml:"extensions"`
+	Description string   `yaml:"description"`
+	Blocks      []string `yaml:"blocks"`
+}
+
+// Find returns the path of the configuration file of the modules within directory: the first found
+// within directory or one of its parents. It returns "" if there is none.
+func Find(directory string) (string, error) {
+	dir, err := filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load returns the configuration of the modules within directory, read from the file found by Find,
+// or the zero Config if there is none.
+func Load(directory string) (*Config, error) {
+	path, err := Find(directory)
+	if err != nil || path == "" {
+		return &Config{}, err
+	}
+	return Read(path)
+}
+
+// Read reads and validates the configuration file at path. Keys that are not part of the
+// configuration are errors.
+func Read(path string) (*Config, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(body))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	config.Path = path
+	for i, template := range config.Templates {
+		if !filepath.IsAbs(template) {
+			config.Templates[i] = filepath.Join(filepath.Dir(path), template)
+		}
+	}
+
+	return config, nil
+}
+
+// Validate returns an error if the configuration names an unknown format, sort order, section, lint
+// rule or severity, or has invalid conventions or patterns.
+func (c *Config) Validate() error {
+	if c.Format != "" {
+		if _, _, err := render.ForFormat(c.Format); err != nil {
+			return err
+		}
+	}
+	switch c.Sort {
+	case "", SortPosition, SortName:
+	default:
+		return fmt.Errorf("unknown sort order %q, expected %s or %s", c.Sort, SortPosition, SortName)
+	}
+	if _, err := render.OnlySections(c.Sections); err != nil {
+		return err
+	}
+	if _, err := lint.New(c.LintConfig()); err != nil {
+		return err
+	}
+	if err := c.Options().Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Options returns the options for finding and parsing modules that the configuration sets:
+// Conventions, Include and Exclude.
+func (c *Config) Options() tf_docs.Options {
+	return tf_docs.Options{
+		Conventions: &tf_docs.Conventions{
+			Extensions:  c.Conventions.Extensions,
+			Description: c.Conventions.Description,
+			Blocks:      c.Conventions.Blocks,
+		},
+		Include: c.Include,
+		Exclude: c.Exclude,
+	}
+}
+
+// LintConfig returns the configuration of the lint rules.
+func (c *Config) LintConfig() lint.Config {
+	config := lint.Config{}
+	for _, id := range c.Lint.Disable {
+		rule := config[id]
+		rule.Disabled = true
+		config[id] = rule
+	}
+	for id, severity := range c.Lint.Severity {
+		rule := config[id]
+		rule.Severity = severity
+		config[id] = rule
+	}
+	return config
+}
+
+// Flags returns the values of the command line flags the configuration sets, by flag name: format,
+// template, disable and severity.
+func (c *Config) Flags() map[string]string {
+	flags := map[string]string{}
+	if c.Format != "" {
+		flags["format"] = c.Format
+	}
+	if len(c.Templates) > 0 {
+		flags["template"] = strings.Join(c.Templates, ",")
+	}
+	if len(c.Lint.Disable) > 0 {
+		flags["disable"] = strings.Join(c.Lint.Disable, ",")
+	}
+	if len(c.Lint.Severity) > 0 {
+		var pairs []string
+		for id, severity := range c.Lint.Severity {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", id, severity))
+		}
+		sort.Strings(pairs)
+		flags["severity"] = strings.Join(pairs, ",")
+	}
+	return flags
+}
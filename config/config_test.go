@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := []struct {
+		Body   string
+		Config *Config
+		Error  string
+	}{
+		{
+			Body: `format: json
+templates: [templates/docs.md.tmpl]
+include: ["modules/*"]
+exclude: [examples]
+sort: name
+sections: [description, variables, outputs]
+lint:
+  disable: [lead-comment]
+  severity:
+    module-description: error
+conventions:
+  extensions: [.tf, .tf.json]
+  description: first-comment
+  blocks: [variable, output, module]
+`,
+			Config: &Config{
+				Format:    "json",
+				Templates: []string{filepath.Join(dir, "templates/docs.md.tmpl")},
+				Include:   []string{"modules/*"},
+				Exclude:   []string{"examples"},
+				Sort:      SortName,
+				Sections:  []string{"description", "variables", "outputs"},
+				Lint: Lint{
+					Disable:  []string{"lead-comment"},
+					Severity: map[string]lint.Severity{"module-description": lint.Error},
+				},
+				Conventions: Conventions{
+					Extensions:  []string{".tf", ".tf.json"},
+					Description: tf_docs.DescriptionFirstComment,
+					Blocks:      []string{"variable", "output", "module"},
+				},
+				Path: filepath.Join(dir, FileName),
+			},
+		},
+		{
+			Body:   "",
+			Config: &Config{Path: filepath.Join(dir, FileName)},
+		},
+		{
+			Body:  "formats: json\n",
+			Error: "field formats not found in type config.Config",
+		},
+		{
+			Body:  "lint:\n  disabled: [lead-comment]\n",
+			Error: "field disabled not found in type config.Lint",
+		},
+		{
+			Body:  "format: docx\n",
+			Error: `unknown format "docx"`,
+		},
+		{
+			Body:  "sort: size\n",
+			Error: `unknown sort order "size", expected position or name`,
+		},
+		{
+			Body:  "sections: [inputs]\n",
+			Error: `unknown section "inputs"`,
+		},
+		{
+			Body:  "lint:\n  disable: [missing-rule]\n",
+			Error: `unknown lint rule "missing-rule"`,
+		},
+		{
+			Body:  "lint:\n  severity:\n    lead-comment: fatal\n",
+			Error: `"fatal"`,
+		},
+		{
+			Body:  "conventions:\n  blocks: [moved]\n",
+			Error: `unknown block type "moved"`,
+		},
+		{
+			Body:  "exclude: [\"[\"]\n",
+			Error: `invalid pattern "["`,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("read %v", i), func(t *testing.T) {
+			path := filepath.Join(dir, FileName)
+			assert.NoError(t, ioutil.WriteFile(path, []byte(c.Body), 0644), "")
+			config, err := Read(path)
+			if c.Error != "" {
+				if assert.Error(t, err, "") {
+					assert.Contains(t, err.Error(), path+": ", "")
+					assert.Contains(t, err.Error(), c.Error, "")
+				}
+				return
+			}
+			assert.NoError(t, err, "")
+			assert.Equal(t, c.Config, config, "")
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	modules := filepath.Join(root, "modules", "vpc")
+	assert.NoError(t, os.MkdirAll(modules, 0755), "")
+
+	path, err := Find(modules)
+	assert.NoError(t, err, "")
+	assert.Equal(t, "", path, "")
+	config, err := Load(modules)
+	assert.NoError(t, err, "")
+	assert.Equal(t, &Config{}, config, "")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, FileName), []byte("format: yaml\n"), 0644), "")
+	for _, dir := range []string{root, modules} {
+		path, err := Find(dir)
+		assert.NoError(t, err, "")
+		assert.Equal(t, filepath.Join(root, FileName), path, "the file should be found from %s", dir)
+	}
+	config, err = Load(modules)
+	assert.NoError(t, err, "")
+	assert.Equal(t, "yaml", config.Format, "")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(modules, FileName), []byte("format: toml\n"), 0644), "")
+	config, err = Load(modules)
+	assert.NoError(t, err, "")
+	assert.Equal(t, "toml", config.Format, "the nearest file should be used")
+}
+
+func TestFlags(t *testing.T) {
+	config := &Config{
+		Format:    "json",
+		Templates: []string{"a.tmpl", "b.tmpl"},
+		Lint: Lint{
+			Disable:  []string{"lead-comment", "snake-case"},
+			Severity: map[string]lint.Severity{"module-description": lint.Error, "lead-comment": lint.Info},
+		},
+	}
+	assert.Equal(t, map[string]string{
+		"format":   "json",
+		"template": "a.tmpl,b.tmpl",
+		"disable":  "lead-comment,snake-case",
+		"severity": "lead-comment=info,module-description=error",
+	}, config.Flags(), "")
+	assert.Empty(t, (&Config{}).Flags(), "")
+}
+
+func TestOptions(t *testing.T) {
+	config := &Config{
+		Include:     []string{"depth*"},
+		Exclude:     []string{"*/module2"},
+		Conventions: Conventions{Description: tf_docs.DescriptionFirstComment},
+	}
+	dirs, err := tf_docs.FindModuleDirs("../testdata/modules", config.Options())
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{"../testdata/modules/depth1", "../testdata/modules/depth2/module1"}, dirs, "")
+
+	assert.Equal(t, lint.Config{
+		"lead-comment":       {Disabled: true},
+		"module-description": {Severity: lint.Error},
+	}, (&Config{Lint: Lint{Disable: []string{"lead-comment"}, Severity: map[string]lint.Severity{"module-description": lint.Error}}}).LintConfig(), "")
+}
@@ -0,0 +1,165 @@
+package tf_docs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// DescriptionModuleName describes a module with the first comment, on the first line of one of
+	// its files, that starts with the module's name.
+	DescriptionModuleName = "module-name"
+	// DescriptionFirstComment describes a module with the first comment on the first line of one of
+	// its files, whatever it starts with.
+	DescriptionFirstComment = "first-comment"
+)
+
+// blockTypes are the types of blocks that are extracted from a module. Provider covers both provider
+// blocks and the requirements of terraform blocks.
+var blockTypes = []string{VARIABLE, OUTPUT, RESOURCE, DATA, MODULE, LOCALS, PROVIDER}
+
+// Conventions are the conventions a project's Terraform files follow: which files make up a module,
+// which comment describes it and which blocks are documented. The zero value follows the conventions
+// of Terraform itself, as ParseFiles and ParseHCL2Files do.
+type Conventions struct {
+	// Extensions are the suffixes of the files that make up a module. Defaults to .tf.
+	Extensions []string
+	// Description is the comment that describes a module, DescriptionModuleName or
+	// DescriptionFirstComment. Defaults to DescriptionModuleName.
+	Description string
+	// Blocks are the types of block that are extracted, as returned by BlockTypes. Defaults to all
+	// of them. The blocks that are not extracted still count towards the UsedBy of variables.
+	Blocks []string
+}
+
+// BlockTypes returns the types of block that can be extracted from a module.
+func BlockTypes() []string {
+	return append([]string(nil), blockTypes...)
+}
+
+// Validate returns an error if the conventions name an unknown description convention or block type,
+// or an empty extension.
+func (c *Conventions) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, ext := range c.Extensions {
+		if ext == "" {
+			return fmt.Errorf("extensions cannot be empty")
+		}
+	}
+	switch c.Description {
+	case "", DescriptionModuleName, DescriptionFirstComment:
+	default:
+		return fmt.Errorf("unknown description convention %q, expected %s or %s", c.Description, DescriptionModuleName, DescriptionFirstComment)
+	}
+	for _, block := range c.Blocks {
+		if !contains(blockTypes, block) {
+			return fmt.Errorf("unknown block type %q, expected one of %s", block, strings.Join(blockTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// ParseFiles behaves like the ParseFiles function, following the conventions.
+func (c *Conventions) ParseFiles(files []*File, moduleName string) (*TFModule, error) {
+	return parseFiles(files, moduleName, c)
+}
+
+// ParseHCL2Files behaves like the ParseHCL2Files function, following the conventions.
+func (c *Conventions) ParseHCL2Files(files []*File, moduleName string) (*TFModule, error) {
+	return parseHCL2Files(files, moduleName, c)
+}
+
+// ListModuleFiles returns the files within a directory that have one of the conventions' extensions.
+func (c *Conventions) ListModuleFiles(directory string) ([]string, error) {
+	files := []string{}
+
+	fileInfos, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return files, err
+	}
+	for _, i := range fileInfos {
+		if c.IsModuleFile(i.Name()) {
+			files = append(files, i.Name())
+		}
+	}
+
+	return files, nil
+}
+
+// IsModuleFile reports whether a file with the given name is part of a module, having one of the
+// conventions' extensions.
+func (c *Conventions) IsModuleFile(name string) bool {
+	extensions := []string{".tf"}
+	if c != nil && len(c.Extensions) > 0 {
+		extensions = c.Extensions
+	}
+	for _, ext := range extensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// extracts reports whether blocks of the given type are extracted.
+func (c *Conventions) extracts(block string) bool {
+	return c == nil || len(c.Blocks) == 0 || contains(c.Blocks, block)
+}
+
+// filter removes the blocks of a module that are not extracted, along with the dependencies of
+// those blocks. Terraform blocks are extracted along with provider blocks.
+func (c *Conventions) filter(module *TFModule) {
+	if c == nil || len(c.Blocks) == 0 {
+		return
+	}
+
+	if !c.extracts(VARIABLE) {
+		module.Variables = nil
+	}
+	if !c.extracts(OUTPUT) {
+		module.Outputs = nil
+	}
+	if !c.extracts(RESOURCE) {
+		module.Resources = nil
+	}
+	if !c.extracts(DATA) {
+		module.DataSources = nil
+	}
+	if !c.extracts(MODULE) {
+		module.Modules = nil
+	}
+	if !c.extracts(LOCALS) {
+		module.Locals = nil
+	}
+	if !c.extracts(PROVIDER) {
+		module.Providers = nil
+		module.RequiredVersion = ""
+	}
+	module.Dependencies = extractDependencies(module)
+}
+
+// description returns the description of a module from the comments of its files.
+func (c *Conventions) description(comments []*Comment, moduleName string) string {
+	if c == nil || c.Description != DescriptionFirstComment {
+		return extractDescription(comments, moduleName)
+	}
+	for _, comment := range comments {
+		if comment.Line == 1 {
+			return strings.TrimSpace(comment.Text)
+		}
+	}
+	return ""
+}
+
+// contains reports whether s is one of list.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
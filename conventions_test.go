@@ -0,0 +1,156 @@
+package tf_docs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConventionsValidate(t *testing.T) {
+	cases := []struct {
+		Conventions *Conventions
+		Error       string
+	}{
+		{
+			Conventions: nil,
+		},
+		{
+			Conventions: &Conventions{Extensions: []string{".tf", ".tf.json"}, Description: DescriptionFirstComment, Blocks: []string{VARIABLE, PROVIDER}},
+		},
+		{
+			Conventions: &Conventions{Extensions: []string{""}},
+			Error:       "extensions cannot be empty",
+		},
+		{
+			Conventions: &Conventions{Description: "header"},
+			Error:       `unknown description convention "header", expected module-name or first-comment`,
+		},
+		{
+			Conventions: &Conventions{Blocks: []string{"moved"}},
+			Error:       `unknown block type "moved", expected one of variable, output, resource, data, module, locals, provider`,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("validate %v", i), func(t *testing.T) {
+			err := c.Conventions.Validate()
+			if c.Error == "" {
+				assert.NoError(t, err, "")
+				return
+			}
+			assert.EqualError(t, err, c.Error, "")
+		})
+	}
+}
+
+func TestConventionsParse(t *testing.T) {
+	src := `# Creates the network for an application
+
+variable "cidr" {}
+
+terraform {
+  required_version = ">= 0.12"
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = var.cidr
+}
+
+output "id" {
+  value = aws_vpc.main.id
+}
+`
+	files := []*File{{Name: "main.tf", Body: src}}
+
+	module, err := (&Conventions{}).ParseHCL2Files(files, "vpc")
+	assert.NoError(t, err, "")
+	expected, err := ParseHCL2Files(files, "vpc")
+	assert.NoError(t, err, "")
+	assert.Equal(t, expected, module, "the zero value should follow the default conventions")
+	assert.Equal(t, "", module.Description, "")
+
+	conventions := &Conventions{Description: DescriptionFirstComment, Blocks: []string{VARIABLE, RESOURCE}}
+	for _, parse := range []ParseFunc{conventions.ParseFiles, conventions.ParseHCL2Files} {
+		module, err := parse([]*File{{Name: "main.tf", Body: `# Creates the network for an application
+
+variable "cidr" {
+  type = "string"
+}
+
+terraform {
+  required_version = ">= 0.12"
+}
+
+resource "aws_vpc" "main" {}
+
+output "id" {
+  value = "id"
+}
+`}}, "vpc")
+		assert.NoError(t, err, "")
+		assert.Equal(t, "Creates the network for an application", module.Description, "")
+		assert.Len(t, module.Variables, 1, "")
+		assert.Len(t, module.Resources, 1, "")
+		assert.Empty(t, module.Outputs, "")
+		assert.Empty(t, module.RequiredVersion, "terraform blocks should be extracted with providers")
+		assert.Empty(t, module.Dependencies, "")
+	}
+}
+
+func TestConventionsListModuleFiles(t *testing.T) {
+	files, err := (&Conventions{Extensions: []string{"file.tf", ".md"}}).ListModuleFiles("./testdata/modules/depth1")
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{"file.tf"}, files, "")
+
+	assert.True(t, (*Conventions)(nil).IsModuleFile("main.tf"), "")
+	assert.False(t, (*Conventions)(nil).IsModuleFile("main.tf.json"), "")
+	assert.True(t, (&Conventions{Extensions: []string{".tf", ".tf.json"}}).IsModuleFile("main.tf.json"), "")
+}
+
+func TestFindModuleDirs(t *testing.T) {
+	cases := []struct {
+		Options Options
+		Dirs    []string
+	}{
+		{
+			Dirs: []string{"./testdata/modules/calls/app", "./testdata/modules/calls/vpc", "./testdata/modules/defaults/a", "./testdata/modules/defaults/b", "./testdata/modules/depth1", "./testdata/modules/depth2/module1", "./testdata/modules/depth2/module2", "./testdata/modules/variables"},
+		},
+		{
+			Options: Options{Include: []string{"depth*"}},
+			Dirs:    []string{"./testdata/modules/depth1", "./testdata/modules/depth2/module1", "./testdata/modules/depth2/module2"},
+		},
+		{
+			Options: Options{Include: []string{"depth*"}, Exclude: []string{"depth2/module2"}},
+			Dirs:    []string{"./testdata/modules/depth1", "./testdata/modules/depth2/module1"},
+		},
+		{
+			Options: Options{Exclude: []string{"calls", "defaults", "*/module*"}},
+			Dirs:    []string{"./testdata/modules/depth1", "./testdata/modules/variables"},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("findModuleDirs %v", i), func(t *testing.T) {
+			dirs, err := FindModuleDirs("./testdata/modules", c.Options)
+			assert.NoError(t, err, "")
+			assert.Equal(t, c.Dirs, dirs, "")
+		})
+	}
+
+	root := t.TempDir()
+	for _, dir := range []string{"vpc", "vpc/.terraform/modules/subnet", ".git"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755), "")
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(root, dir, "main.tf"), []byte(""), 0644), "")
+	}
+	dirs, err := FindModuleDirs(root, Options{})
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{root + "/vpc"}, dirs, "hidden directories and .terraform should be skipped")
+
+	assert.Error(t, Options{Exclude: []string{"["}}.Validate(), "")
+	assert.Error(t, Options{Conventions: &Conventions{Blocks: []string{"moved"}}}.Validate(), "")
+	assert.NoError(t, Options{Include: []string{"modules/*"}}.Validate(), "")
+}
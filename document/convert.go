@@ -0,0 +1,229 @@
+package document
+
+import (
+	"github.com/nathmclean/tf_docs"
+)
+
+// New returns the Document of a set of modules, such as the result of FindAndParse.
+func New(modules []*tf_docs.TFModule) *Document {
+	d := &Document{SchemaVersion: SchemaVersion, Modules: []*Module{}}
+
+	for _, m := range modules {
+		module := &Module{
+			Path:            m.Path,
+			Title:           m.Title,
+			Link:            m.Link,
+			Description:     m.Description,
+			RequiredVersion: m.RequiredVersion,
+		}
+		for _, p := range m.Providers {
+			module.Providers = append(module.Providers, &Provider{
+				Name:       p.Name,
+				Alias:      p.Alias,
+				Source:     p.Source,
+				Version:    p.Version,
+				Range:      fromRange(p.Range),
+				References: fromReferences(p.References),
+			})
+		}
+		for _, v := range m.Variables {
+			module.Variables = append(module.Variables, &Variable{
+				Name:        v.Name,
+				Type:        v.Type,
+				Description: v.Description,
+				Default:     v.Default,
+				Required:    v.Required,
+				Range:       fromRange(v.Range),
+				UsedBy:      v.UsedBy,
+			})
+		}
+		for _, o := range m.Outputs {
+			module.Outputs = append(module.Outputs, &Output{
+				Name:        o.Name,
+				Description: o.Description,
+				Range:       fromRange(o.Range),
+				References:  fromReferences(o.References),
+				Exposes:     o.Exposes,
+			})
+		}
+		for _, l := range m.Locals {
+			module.Locals = append(module.Locals, &Local{
+				Name:       l.Name,
+				Range:      fromRange(l.Range),
+				References: fromReferences(l.References),
+			})
+		}
+		for _, r := range m.Resources {
+			module.Resources = append(module.Resources, &Resource{
+				Type:        r.Type,
+				Name:        r.Name,
+				Description: r.Description,
+				Range:       fromRange(r.Range),
+				References:  fromReferences(r.References),
+			})
+		}
+		for _, r := range m.DataSources {
+			module.DataSources = append(module.DataSources, &Resource{
+				Type:        r.Type,
+				Name:        r.Name,
+				Description: r.Description,
+				Range:       fromRange(r.Range),
+				References:  fromReferences(r.References),
+			})
+		}
+		for _, c := range m.Modules {
+			module.ModuleCalls = append(module.ModuleCalls, &ModuleCall{
+				Name:        c.Name,
+				Description: c.Description,
+				Source:      c.Source,
+				Version:     c.Version,
+				Providers:   c.Providers,
+				Count:       c.Count,
+				ForEach:     c.ForEach,
+				DependsOn:   c.DependsOn,
+				Inputs:      c.Inputs,
+				Range:       fromRange(c.Range),
+				References:  fromReferences(c.References),
+			})
+		}
+		for _, dep := range m.Dependencies {
+			module.Dependencies = append(module.Dependencies, &Dependency{
+				From:  dep.From,
+				To:    dep.To,
+				Range: fromRange(dep.Range),
+			})
+		}
+
+		d.Modules = append(d.Modules, module)
+	}
+
+	return d
+}
+
+// TFModules returns the modules of a Document.
+func (d *Document) TFModules() []*tf_docs.TFModule {
+	var modules []*tf_docs.TFModule
+
+	for _, m := range d.Modules {
+		module := &tf_docs.TFModule{
+			Path:            m.Path,
+			Title:           m.Title,
+			Link:            m.Link,
+			Description:     m.Description,
+			RequiredVersion: m.RequiredVersion,
+		}
+		for _, p := range m.Providers {
+			module.Providers = append(module.Providers, &tf_docs.Provider{
+				Name:       p.Name,
+				Alias:      p.Alias,
+				Source:     p.Source,
+				Version:    p.Version,
+				Range:      p.Range.tfRange(),
+				References: tfReferences(p.References),
+			})
+		}
+		for _, v := range m.Variables {
+			module.Variables = append(module.Variables, &tf_docs.Variable{
+				Name:        v.Name,
+				Type:        v.Type,
+				Description: v.Description,
+				Default:     v.Default,
+				Required:    v.Required,
+				Range:       v.Range.tfRange(),
+				UsedBy:      v.UsedBy,
+			})
+		}
+		for _, o := range m.Outputs {
+			module.Outputs = append(module.Outputs, &tf_docs.Output{
+				Name:        o.Name,
+				Description: o.Description,
+				Range:       o.Range.tfRange(),
+				References:  tfReferences(o.References),
+				Exposes:     o.Exposes,
+			})
+		}
+		for _, l := range m.Locals {
+			module.Locals = append(module.Locals, &tf_docs.Local{
+				Name:       l.Name,
+				Range:      l.Range.tfRange(),
+				References: tfReferences(l.References),
+			})
+		}
+		for _, r := range m.Resources {
+			module.Resources = append(module.Resources, &tf_docs.Resource{
+				Type:        r.Type,
+				Name:        r.Name,
+				Description: r.Description,
+				Range:       r.Range.tfRange(),
+				References:  tfReferences(r.References),
+			})
+		}
+		for _, r := range m.DataSources {
+			module.DataSources = append(module.DataSources, &tf_docs.DataSource{
+				Type:        r.Type,
+				Name:        r.Name,
+				Description: r.Description,
+				Range:       r.Range.tfRange(),
+				References:  tfReferences(r.References),
+			})
+		}
+		for _, c := range m.ModuleCalls {
+			module.Modules = append(module.Modules, &tf_docs.Module{
+				Name:        c.Name,
+				Description: c.Description,
+				Source:      c.Source,
+				Version:     c.Version,
+				Providers:   c.Providers,
+				Count:       c.Count,
+				ForEach:     c.ForEach,
+				DependsOn:   c.DependsOn,
+				Inputs:      c.Inputs,
+				Range:       c.Range.tfRange(),
+				References:  tfReferences(c.References),
+			})
+		}
+		for _, dep := range m.Dependencies {
+			module.Dependencies = append(module.Dependencies, &tf_docs.Dependency{
+				From:  dep.From,
+				To:    dep.To,
+				Range: dep.Range.tfRange(),
+			})
+		}
+
+		modules = append(modules, module)
+	}
+
+	return modules
+}
+
+func fromRange(r tf_docs.Range) Range {
+	return Range{
+		Filename: r.Filename,
+		Start:    Pos{Line: r.Start.Line, Column: r.Start.Column},
+		End:      Pos{Line: r.End.Line, Column: r.End.Column},
+	}
+}
+
+func (r Range) tfRange() tf_docs.Range {
+	return tf_docs.Range{
+		Filename: r.Filename,
+		Start:    tf_docs.Pos{Line: r.Start.Line, Column: r.Start.Column},
+		End:      tf_docs.Pos{Line: r.End.Line, Column: r.End.Column},
+	}
+}
+
+func fromReferences(references []*tf_docs.Reference) []*Reference {
+	var result []*Reference
+	for _, r := range references {
+		result = append(result, &Reference{Name: r.Name, Range: fromRange(r.Range)})
+	}
+	return result
+}
+
+func tfReferences(references []*Reference) []*tf_docs.Reference {
+	var result []*tf_docs.Reference
+	for _, r := range references {
+		result = append(result, &tf_docs.Reference{Name: r.Name, Range: r.Range.tfRange()})
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+// Package document defines a stable, versioned format for the modules found by tf_docs, so they can
+// be written out and read back by other tools. The format can be written as JSON, YAML or TOML, with
+// the same field names in each. The JSON Schema of the format is in schema.json.
+package document
+
+import (
+	_ "embed"
+)
+
+// SchemaVersion is the version of the format written by this package. It changes whenever a field is
+// renamed or removed, or its meaning changes.
+const SchemaVersion = "1"
+
+// Schema is the JSON Schema describing a Document.
+//
+//go:embed schema.json
+var Schema []byte
+
+// Document is the top level of the format: the schema version and every module.
+type Document struct {
+	SchemaVersion string    `json:"schema_version" yaml:"schema_version" toml:"schema_version"`
+	Modules       []*Module `json:"modules" yaml:"modules" toml:"modules"`
+}
+
+// Module is a tf_docs.TFModule. Its Dir is left out, as it depends on the directory tf_docs was run
+// from, so a module read from a Document has no Dir. Path and Title place it within the directory
+// searched.
+type Module struct {
+	Path            string        `json:"path" yaml:"path" toml:"path"`
+	Title           string        `json:"title" yaml:"title" toml:"title"`
+	Link            string        `json:"link" yaml:"link" toml:"link"`
+	Description     string        `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	RequiredVersion string        `json:"required_version,omitempty" yaml:"required_version,omitempty" toml:"required_version,omitempty"`
+	Providers       []*Provider   `json:"providers,omitempty" yaml:"providers,omitempty" toml:"providers,omitempty"`
+	Variables       []*Variable   `json:"variables,omitempty" yaml:"variables,omitempty" toml:"variables,omitempty"`
+	Outputs         []*Output     `json:"outputs,omitempty" yaml:"outputs,omitempty" toml:"outputs,omitempty"`
+	Locals          []*Local      `json:"locals,omitempty" yaml:"locals,omitempty" toml:"locals,omitempty"`
+	Resources       []*Resource   `json:"resources,omitempty" yaml:"resources,omitempty" toml:"resources,omitempty"`
+	DataSources     []*Resource   `json:"data_sources,omitempty" yaml:"data_sources,omitempty" toml:"data_sources,omitempty"`
+	ModuleCalls     []*ModuleCall `json:"module_calls,omitempty" yaml:"module_calls,omitempty" toml:"module_calls,omitempty"`
+	Dependencies    []*Dependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty" toml:"dependencies,omitempty"`
+}
+
+// Provider is a tf_docs.Provider.
+type Provider struct {
+	Name       string       `json:"name" yaml:"name" toml:"name"`
+	Alias      string       `json:"alias,omitempty" yaml:"alias,omitempty" toml:"alias,omitempty"`
+	Source     string       `json:"source,omitempty" yaml:"source,omitempty" toml:"source,omitempty"`
+	Version    string       `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+	Range      Range        `json:"range" yaml:"range" toml:"range"`
+	References []*Reference `json:"references,omitempty" yaml:"references,omitempty" toml:"references,omitempty"`
+}
+
+// Variable is a tf_docs.Variable.
+type Variable struct {
+	Name        string   `json:"name" yaml:"name" toml:"name"`
+	Type        string   `json:"type,omitempty" yaml:"type,omitempty" toml:"type,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Default     string   `json:"default,omitempty" yaml:"default,omitempty" toml:"default,omitempty"`
+	Required    bool     `json:"required" yaml:"required" toml:"required"`
+	Range       Range    `json:"range" yaml:"range" toml:"range"`
+	UsedBy      []string `json:"used_by,omitempty" yaml:"used_by,omitempty" toml:"used_by,omitempty"`
+}
+
+// Output is a tf_docs.Output.
+type Output struct {
+	Name        string       `json:"name" yaml:"name" toml:"name"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Range       Range        `json:"range" yaml:"range" toml:"range"`
+	References  []*Reference `json:"references,omitempty" yaml:"references,omitempty" toml:"references,omitempty"`
+	Exposes     []string     `json:"exposes,omitempty" yaml:"exposes,omitempty" toml:"exposes,omitempty"`
+}
+
+// Local is a tf_docs.Local.
+type Local struct {
+	Name       string       `json:"name" yaml:"name" toml:"name"`
+	Range      Range        `json:"range" yaml:"range" toml:"range"`
+	References []*Reference `json:"references,omitempty" yaml:"references,omitempty" toml:"references,omitempty"`
+}
+
+// Resource is a tf_docs.Resource or a tf_docs.DataSource.
+type Resource struct {
+	Type        string       `json:"type" yaml:"type" toml:"type"`
+	Name        string       `json:"name" yaml:"name" toml:"name"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Range       Range        `json:"range" yaml:"range" toml:"range"`
+	References  []*Reference `json:"references,omitempty" yaml:"references,omitempty" toml:"references,omitempty"`
+}
+
+// ModuleCall is a tf_docs.Module: a call to another module.
+type ModuleCall struct {
+	Name        string            `json:"name" yaml:"name" toml:"name"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Source      string            `json:"source" yaml:"source" toml:"source"`
+	Version     string            `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty"`
+	Providers   map[string]string `json:"providers,omitempty" yaml:"providers,omitempty" toml:"providers,omitempty"`
+	Count       string            `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty"`
+	ForEach     string            `json:"for_each,omitempty" yaml:"for_each,omitempty" toml:"for_each,omitempty"`
+	DependsOn   []string          `json:"depends_on,omitempty" yaml:"depends_on,omitempty" toml:"depends_on,omitempty"`
+	Inputs      map[string]string `json:"inputs,omitempty" yaml:"inputs,omitempty" toml:"inputs,omitempty"`
+	Range       Range             `json:"range" yaml:"range" toml:"range"`
+	References  []*Reference      `json:"references,omitempty" yaml:"references,omitempty" toml:"references,omitempty"`
+}
+
+// Dependency is a tf_docs.Dependency.
+type Dependency struct {
+	From  string `json:"from" yaml:"from" toml:"from"`
+	To    string `json:"to" yaml:"to" toml:"to"`
+	Range Range  `json:"range" yaml:"range" toml:"range"`
+}
+
+// Reference is a tf_docs.Reference.
+type Reference struct {
+	Name  string `json:"name" yaml:"name" toml:"name"`
+	Range Range  `json:"range" yaml:"range" toml:"range"`
+}
+
+// Range is a tf_docs.Range.
+type Range struct {
+	Filename string `json:"filename,omitempty" yaml:"filename,omitempty" toml:"filename,omitempty"`
+	Start    Pos    `json:"start" yaml:"start" toml:"start"`
+	End      Pos    `json:"end" yaml:"end" toml:"end"`
+}
+
+// Pos is a tf_docs.Pos.
+type Pos struct {
+	Line   int `json:"line" yaml:"line" toml:"line"`
+	Column int `json:"column" yaml:"column" toml:"column"`
+}
@@ -0,0 +1,143 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// fixtures are the directories within ../testdata/modules with golden files in testdata.
+var fixtures = []string{"depth1", "depth2", "calls", "variables"}
+
+// formats are the encodings of a Document, by the extension of their golden files.
+var formats = []struct {
+	Extension string
+	Write     func(io.Writer, []*tf_docs.TFModule) error
+	Read      func(io.Reader) ([]*tf_docs.TFModule, error)
+}{
+	{Extension: ".json", Write: WriteJSON, Read: ReadJSON},
+	{Extension: ".yaml", Write: WriteYAML, Read: ReadYAML},
+	{Extension: ".toml", Write: WriteTOML, Read: ReadTOML},
+}
+
+func TestGolden(t *testing.T) {
+	for _, name := range fixtures {
+		result, err := tf_docs.FindAndParseWithOptions(filepath.Join("../testdata/modules", name), tf_docs.Options{Parser: tf_docs.ParseHCL2Files})
+		assert.NoError(t, err, "")
+		modules := result.Modules
+
+		for _, format := range formats {
+			t.Run(fmt.Sprintf("Golden %v%v", name, format.Extension), func(t *testing.T) {
+				golden := filepath.Join("testdata", name+format.Extension)
+
+				var buf bytes.Buffer
+				assert.NoError(t, format.Write(&buf, modules), "")
+				if *update {
+					assert.NoError(t, ioutil.WriteFile(golden, buf.Bytes(), 0644), "")
+				}
+				expected, err := ioutil.ReadFile(golden)
+				assert.NoError(t, err, "")
+				assert.Equal(t, string(expected), buf.String(), "run go test -update to update the golden files")
+
+				loaded, err := format.Read(bytes.NewReader(expected))
+				assert.NoError(t, err, "")
+				// Dir is not part of a Document.
+				var withoutDir []*tf_docs.TFModule
+				for _, module := range modules {
+					m := *module
+					m.Dir = ""
+					withoutDir = append(withoutDir, &m)
+				}
+				assert.Equal(t, withoutDir, loaded, "")
+			})
+		}
+	}
+}
+
+func TestReadVersion(t *testing.T) {
+	documents := map[string]string{
+		".json": `{"schema_version": "0", "modules": []}`,
+		".yaml": "schema_version: \"0\"\nmodules: []\n",
+		".toml": "schema_version = \"0\"\nmodules = []\n",
+	}
+	for _, format := range formats {
+		t.Run(fmt.Sprintf("ReadVersion %v", format.Extension), func(t *testing.T) {
+			_, err := format.Read(strings.NewReader(documents[format.Extension]))
+			assert.ErrorIs(t, err, ErrUnsupportedVersion, "")
+		})
+	}
+}
+
+// schemaDefinitions maps the types of the format to their definition within the JSON Schema.
+var schemaDefinitions = map[reflect.Type]string{
+	reflect.TypeOf(Module{}):     "module",
+	reflect.TypeOf(Provider{}):   "provider",
+	reflect.TypeOf(Variable{}):   "variable",
+	reflect.TypeOf(Output{}):     "output",
+	reflect.TypeOf(Local{}):      "local",
+	reflect.TypeOf(Resource{}):   "resource",
+	reflect.TypeOf(ModuleCall{}): "module_call",
+	reflect.TypeOf(Dependency{}): "dependency",
+	reflect.TypeOf(Reference{}):  "reference",
+	reflect.TypeOf(Range{}):      "range",
+	reflect.TypeOf(Pos{}):        "pos",
+}
+
+type schemaObject struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// TestSchema checks that the JSON Schema describes every field of the format, and requires exactly
+// the fields that are always written. YAML and TOML must use the same names as JSON.
+func TestSchema(t *testing.T) {
+	var schema struct {
+		schemaObject
+		Defs map[string]schemaObject `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(Schema, &schema), "")
+
+	check := func(typ reflect.Type, object schemaObject) {
+		var fields, required []string
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")
+			for _, encoding := range []string{"yaml", "toml"} {
+				assert.Equal(t, field.Tag.Get("json"), field.Tag.Get(encoding), "%s.%s %s tag", typ.Name(), field.Name, encoding)
+			}
+			fields = append(fields, tag[0])
+			if len(tag) == 1 {
+				required = append(required, tag[0])
+			}
+		}
+		var properties []string
+		for name := range object.Properties {
+			properties = append(properties, name)
+		}
+		sort.Strings(fields)
+		sort.Strings(properties)
+		sort.Strings(required)
+		sort.Strings(object.Required)
+		assert.Equal(t, fields, properties, typ.Name())
+		assert.Equal(t, required, object.Required, typ.Name())
+	}
+
+	check(reflect.TypeOf(Document{}), schema.schemaObject)
+	assert.Equal(t, len(schemaDefinitions), len(schema.Defs), "")
+	for typ, name := range schemaDefinitions {
+		check(typ, schema.Defs[name])
+	}
+}
@@ -0,0 +1,41 @@
+package document
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// ErrUnsupportedVersion is returned when reading a document written with a schema version this
+// package cannot read.
+var ErrUnsupportedVersion = errors.New("unsupported schema version")
+
+// WriteJSON writes the Document of modules as indented JSON.
+func WriteJSON(w io.Writer, modules []*tf_docs.TFModule) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(New(modules))
+}
+
+// ReadJSON reads a Document written by WriteJSON back into modules.
+func ReadJSON(r io.Reader) ([]*tf_docs.TFModule, error) {
+	var d Document
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return nil, err
+	}
+	if err := d.check(); err != nil {
+		return nil, err
+	}
+	return d.TFModules(), nil
+}
+
+// check returns an error unless the document was written with a supported schema version.
+func (d *Document) check() error {
+	if d.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("%w %q, expected %q", ErrUnsupportedVersion, d.SchemaVersion, SchemaVersion)
+	}
+	return nil
+}
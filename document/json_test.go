@@ -0,0 +1,52 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadJSON(t *testing.T) {
+	cases := []struct {
+		Input   string
+		Modules []*tf_docs.TFModule
+		Err     bool
+	}{
+		{
+			Input: `{"schema_version": "1", "modules": [{"path": "", "title": "vpc", "link": "vpc"}]}`,
+			Modules: []*tf_docs.TFModule{
+				{Title: "vpc", Link: "vpc"},
+			},
+		},
+		{
+			Input: `{"schema_version": "1", "modules": []}`,
+		},
+		{
+			Input: `{"schema_version": "2", "modules": []}`,
+			Err:   true,
+		},
+		{
+			Input: `{"modules": []}`,
+			Err:   true,
+		},
+		{
+			Input: `[]`,
+			Err:   true,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("ReadJSON %v", i), func(t *testing.T) {
+			modules, err := ReadJSON(strings.NewReader(c.Input))
+			if c.Err {
+				assert.Error(t, err, "Expected an error")
+			} else {
+				assert.NoError(t, err, "Expected no error")
+				assert.Equal(t, c.Modules, modules, "")
+			}
+		})
+	}
+}
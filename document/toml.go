@@ -0,0 +1,25 @@
+package document
+
+import (
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nathmclean/tf_docs"
+)
+
+// WriteTOML writes the Document of modules as TOML.
+func WriteTOML(w io.Writer, modules []*tf_docs.TFModule) error {
+	return toml.NewEncoder(w).Encode(New(modules))
+}
+
+// ReadTOML reads a Document written by WriteTOML back into modules.
+func ReadTOML(r io.Reader) ([]*tf_docs.TFModule, error) {
+	var d Document
+	if _, err := toml.NewDecoder(r).Decode(&d); err != nil {
+		return nil, err
+	}
+	if err := d.check(); err != nil {
+		return nil, err
+	}
+	return d.TFModules(), nil
+}
@@ -0,0 +1,30 @@
+package document
+
+import (
+	"io"
+
+	"github.com/nathmclean/tf_docs"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteYAML writes the Document of modules as YAML.
+func WriteYAML(w io.Writer, modules []*tf_docs.TFModule) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(New(modules)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// ReadYAML reads a Document written by WriteYAML back into modules.
+func ReadYAML(r io.Reader) ([]*tf_docs.TFModule, error) {
+	var d Document
+	if err := yaml.NewDecoder(r).Decode(&d); err != nil {
+		return nil, err
+	}
+	if err := d.check(); err != nil {
+		return nil, err
+	}
+	return d.TFModules(), nil
+}
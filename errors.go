@@ -0,0 +1,122 @@
+package tf_docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// SyntaxError is returned when a Terraform file cannot be parsed.
+type SyntaxError struct {
+	Filename string
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", location(e.Filename, e.Line, e.Column), e.Message)
+}
+
+// SchemaError is returned when a block is missing something it requires, such as a variable without
+// a type or a module without a source.
+type SchemaError struct {
+	Filename  string
+	Line      int
+	BlockKind string
+	BlockName string
+	Message   string
+}
+
+func (e *SchemaError) Error() string {
+	block := e.BlockKind
+	if e.BlockName != "" {
+		block = fmt.Sprintf("%s %q", e.BlockKind, e.BlockName)
+	}
+	return fmt.Sprintf("%s: %s: %s", location(e.Filename, e.Line, 0), block, e.Message)
+}
+
+// Errors is a list of errors, such as every broken block across a set of modules. Use errors.As to
+// find a SyntaxError or SchemaError within it.
+type Errors []error
+
+func (e Errors) Error() string {
+	var messages []string
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the errors in the list.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// add appends err to the list. When err is itself an Errors, its errors are appended instead.
+func (e *Errors) add(err error) {
+	if list, ok := err.(Errors); ok {
+		*e = append(*e, list...)
+		return
+	}
+	*e = append(*e, err)
+}
+
+// err returns the list as an error, or nil when the list is empty.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// newSchemaError returns a SchemaError for the block a Value was extracted from.
+func newSchemaError(value *Value, kind, message string) *SchemaError {
+	var name string
+	if labels := value.Key[kind]; len(labels) > 0 {
+		name = strings.Join(labels, ".")
+	}
+	return &SchemaError{
+		Filename:  value.Range.Filename,
+		Line:      value.Range.Start.Line,
+		BlockKind: kind,
+		BlockName: name,
+		Message:   message,
+	}
+}
+
+// diagnosticErrors converts the errors within HCL2 diagnostics into SyntaxErrors.
+func diagnosticErrors(diags hcl.Diagnostics) Errors {
+	var errs Errors
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		err := &SyntaxError{Message: diag.Summary}
+		if diag.Detail != "" {
+			err.Message = fmt.Sprintf("%s; %s", diag.Summary, diag.Detail)
+		}
+		if diag.Subject != nil {
+			err.Filename = diag.Subject.Filename
+			err.Line = diag.Subject.Start.Line
+			err.Column = diag.Subject.Start.Column
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// location formats a position within a file, omitting anything that is unknown.
+func location(filename string, line, column int) string {
+	if filename == "" {
+		filename = "<input>"
+	}
+	switch {
+	case line == 0:
+		return filename
+	case column == 0:
+		return fmt.Sprintf("%s:%d", filename, line)
+	}
+	return fmt.Sprintf("%s:%d:%d", filename, line, column)
+}
@@ -0,0 +1,154 @@
+package tf_docs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorMessages(t *testing.T) {
+	cases := []struct {
+		Err     error
+		Message string
+	}{
+		{
+			Err:     &SyntaxError{Filename: "main.tf", Line: 3, Column: 7, Message: "unexpected token"},
+			Message: "main.tf:3:7: unexpected token",
+		},
+		{
+			Err:     &SyntaxError{Message: "unexpected token"},
+			Message: "<input>: unexpected token",
+		},
+		{
+			Err:     &SchemaError{Filename: "main.tf", Line: 3, BlockKind: "variable", BlockName: "test", Message: "type is required for a variable"},
+			Message: "main.tf:3: variable \"test\": type is required for a variable",
+		},
+		{
+			Err:     &SchemaError{Line: 1, BlockKind: "module", Message: "name is required for a module"},
+			Message: "<input>:1: module: name is required for a module",
+		},
+		{
+			Err: Errors{
+				&SyntaxError{Filename: "a.tf", Line: 1, Column: 1, Message: "one"},
+				&SyntaxError{Filename: "b.tf", Line: 2, Column: 2, Message: "two"},
+			},
+			Message: "a.tf:1:1: one\nb.tf:2:2: two",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Error %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Message, c.Err.Error(), "")
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		Parse  ParseFunc
+		Files  []*File
+		Syntax int
+		Schema int
+	}{
+		{
+			Parse: ParseFiles,
+			Files: []*File{
+				{Name: "a.tf", Body: "variable \"a\" {\n  description = \"no type\"\n}\n"},
+				{Name: "b.tf", Body: "module \"b\" {\n}\n\nvariable \"c\" {\n}\n"},
+			},
+			Schema: 3,
+		},
+		{
+			Parse: ParseFiles,
+			Files: []*File{
+				{Name: "a.tf", Body: "variable \"a\" {"},
+				{Name: "b.tf", Body: "output \"b\" {"},
+			},
+			Syntax: 2,
+		},
+		{
+			Parse: ParseHCL2Files,
+			Files: []*File{
+				{Name: "a.tf", Body: "module \"a\" {\n}\n"},
+				{Name: "b.tf", Body: "resource \"b\" {\n}\n"},
+			},
+			Schema: 2,
+		},
+		{
+			Parse: ParseHCL2Files,
+			Files: []*File{
+				{Name: "a.tf", Body: "variable \"a\" {"},
+			},
+			Syntax: 1,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("ParseErrors %v", i), func(t *testing.T) {
+			_, err := c.Parse(c.Files, "test")
+			assert.Error(t, err, "Expected an error")
+
+			var list Errors
+			assert.True(t, errors.As(err, &list), "expected Errors")
+			var syntax, schema int
+			for _, e := range list {
+				switch e := e.(type) {
+				case *SyntaxError:
+					syntax++
+					assert.NotZero(t, e.Line, "expected a line")
+				case *SchemaError:
+					schema++
+					assert.NotZero(t, e.Line, "expected a line")
+				}
+			}
+			assert.Equal(t, c.Syntax, syntax, "")
+			assert.Equal(t, c.Schema, schema, "")
+
+			var syntaxErr *SyntaxError
+			assert.Equal(t, c.Syntax > 0, errors.As(err, &syntaxErr), "")
+			var schemaErr *SchemaError
+			assert.Equal(t, c.Schema > 0, errors.As(err, &schemaErr), "")
+		})
+	}
+}
+
+func TestFindAndParseErrors(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"good/main.tf": "variable \"a\" {\n  type = \"string\"\n}\n",
+		"bad1/main.tf": "variable \"a\" {\n}\n",
+		"bad2/main.tf": "module \"b\" {\n}\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "")
+		assert.NoError(t, ioutil.WriteFile(path, []byte(body), 0644), "")
+	}
+
+	modules, err := FindAndParse(root)
+	assert.Len(t, modules, 1, "the good module should still be parsed")
+
+	var list Errors
+	assert.True(t, errors.As(err, &list), "expected Errors")
+	assert.Equal(t, Errors{
+		&SchemaError{
+			Filename:  root + "/bad1/main.tf",
+			Line:      1,
+			BlockKind: VARIABLE,
+			BlockName: "a",
+			Message:   "type is required for a variable",
+		},
+		&SchemaError{
+			Filename:  root + "/bad2/main.tf",
+			Line:      1,
+			BlockKind: MODULE,
+			BlockName: "b",
+			Message:   "source is required for a module",
+		},
+	}, list, "")
+}
@@ -0,0 +1,121 @@
+// Package graph builds graphs of Terraform modules and writes them as Graphviz DOT or Mermaid.
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Node is a node of a Graph.
+type Node struct {
+	// ID uniquely identifies the node within its graph.
+	ID    string
+	Label string
+	// External marks nodes that are not part of the documented modules, such as registry and git
+	// modules. They are drawn with a dashed outline.
+	External bool
+}
+
+// Edge is a directed edge of a Graph between the nodes with the IDs From and To.
+type Edge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// Graph is a directed graph. Nodes and edges are written in the order they were added.
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+
+	nodes map[string]*Node
+}
+
+// AddNode adds a node to the graph, returning the existing node if one with the same ID was
+// already added.
+func (g *Graph) AddNode(node *Node) *Node {
+	if g.nodes == nil {
+		g.nodes = map[string]*Node{}
+	}
+	if existing, ok := g.nodes[node.ID]; ok {
+		return existing
+	}
+	g.nodes[node.ID] = node
+	g.Nodes = append(g.Nodes, node)
+	return node
+}
+
+// AddEdge adds an edge to the graph.
+func (g *Graph) AddEdge(from, to, label string) {
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Label: label})
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		style := ""
+		if node.External {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s%s];\n", dotQuote(node.ID), dotQuote(node.Label), style)
+	}
+	for _, edge := range g.Edges {
+		label := ""
+		if edge.Label != "" {
+			label = fmt.Sprintf(" [label=%s]", dotQuote(edge.Label))
+		}
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", dotQuote(edge.From), dotQuote(edge.To), label)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+
+	ids := map[string]string{}
+	external := false
+	b.WriteString("graph LR\n")
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		class := ""
+		if node.External {
+			class = ":::external"
+			external = true
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]%s\n", ids[node.ID], mermaidEscape(node.Label), class)
+	}
+	for _, edge := range g.Edges {
+		label := ""
+		if edge.Label != "" {
+			label = fmt.Sprintf("|\"%s\"|", mermaidEscape(edge.Label))
+		}
+		fmt.Fprintf(&b, "  %s -->%s %s\n", ids[edge.From], label, ids[edge.To])
+	}
+	if external {
+		b.WriteString("  classDef external stroke-dasharray: 5 5\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes a string as a DOT ID.
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + s + `"`
+}
+
+// mermaidEscape escapes a string for use within a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.Replace(s, `"`, "#quot;", -1)
+}
@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testGraph() *Graph {
+	g := &Graph{}
+	g.AddNode(&Node{ID: "app", Label: "app"})
+	g.AddNode(&Node{ID: "vpc", Label: "network/\"vpc\""})
+	g.AddNode(&Node{ID: "external:registry", Label: "registry", External: true})
+	g.AddNode(&Node{ID: "app", Label: "duplicate"})
+	g.AddEdge("app", "vpc", "network")
+	g.AddEdge("app", "external:registry", "")
+	return g
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testGraph().WriteDOT(&buf), "")
+	assert.Equal(t, `digraph {
+  rankdir=LR;
+  "app" [label="app"];
+  "vpc" [label="network/\"vpc\""];
+  "external:registry" [label="registry", style=dashed];
+  "app" -> "vpc" [label="network"];
+  "app" -> "external:registry";
+}
+`, buf.String(), "")
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testGraph().WriteMermaid(&buf), "")
+	assert.Equal(t, `graph LR
+  n0["app"]
+  n1["network/#quot;vpc#quot;"]
+  n2["registry"]:::external
+  n0 -->|"network"| n1
+  n0 --> n2
+  classDef external stroke-dasharray: 5 5
+`, buf.String(), "")
+}
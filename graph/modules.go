@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"path/filepath"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// Modules returns the dependency graph of a set of modules, such as the result of FindAndParse.
+// There is a node for each module and an edge, labelled with the call's name, for each module call.
+// Calls to local modules point to the module they resolve to; any other source, such as a registry
+// or git module, is an external node.
+func Modules(modules []*tf_docs.TFModule) *Graph {
+	g := &Graph{}
+
+	for _, module := range modules {
+		g.AddNode(moduleNode(module))
+	}
+	for _, module := range modules {
+		addCalls(g, modules, module)
+	}
+
+	return g
+}
+
+// Module returns the dependency graph of a single module: the module and every module it depends
+// on, directly or through other modules.
+func Module(modules []*tf_docs.TFModule, module *tf_docs.TFModule) *Graph {
+	g := &Graph{}
+
+	g.AddNode(moduleNode(module))
+	visited := map[*tf_docs.TFModule]bool{}
+	queue := []*tf_docs.TFModule{module}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		queue = append(queue, addCalls(g, modules, current)...)
+	}
+
+	return g
+}
+
+// addCalls adds an edge for each module called by caller, adding the nodes they point to, and
+// returns the local modules called.
+func addCalls(g *Graph, modules []*tf_docs.TFModule, caller *tf_docs.TFModule) []*tf_docs.TFModule {
+	var called []*tf_docs.TFModule
+
+	for _, call := range caller.Modules {
+		var to *Node
+		if target := tf_docs.ResolveLocalModule(modules, caller, call); target != nil {
+			to = g.AddNode(moduleNode(target))
+			called = append(called, target)
+		} else {
+			to = g.AddNode(externalNode(caller, call))
+		}
+		g.AddEdge(caller.Link, to.ID, call.Name)
+	}
+
+	return called
+}
+
+// moduleNode returns the node of a documented module.
+func moduleNode(module *tf_docs.TFModule) *Node {
+	label := module.Title
+	if module.Path != "" {
+		label = module.Path + "/" + module.Title
+	}
+	return &Node{ID: module.Link, Label: label}
+}
+
+// externalNode returns the node of a module that is not documented. Local sources are labelled
+// with the path they resolve to.
+func externalNode(caller *tf_docs.TFModule, call *tf_docs.Module) *Node {
+	label := call.Source
+	if tf_docs.IsLocalSource(call.Source) {
+		label = filepath.Clean(filepath.Join(caller.Dir, call.Source))
+	}
+	if call.Version != "" {
+		label += " " + call.Version
+	}
+	return &Node{ID: "external:" + label, Label: label, External: true}
+}
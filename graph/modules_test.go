@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModules(t *testing.T) {
+	result, err := tf_docs.FindAndParseWithOptions("../testdata/modules/calls", tf_docs.Options{Parser: tf_docs.ParseHCL2Files})
+	assert.NoError(t, err, "")
+
+	g := Modules(result.Modules)
+	assert.Equal(t, []*Node{
+		{ID: "app", Label: "app"},
+		{ID: "vpc", Label: "vpc"},
+		{ID: "external:terraform-aws-modules/vpc/aws ~> 2.0", Label: "terraform-aws-modules/vpc/aws ~> 2.0", External: true},
+	}, g.Nodes, "")
+	assert.Equal(t, []*Edge{
+		{From: "app", To: "vpc", Label: "vpc"},
+		{From: "app", To: "external:terraform-aws-modules/vpc/aws ~> 2.0", Label: "registry"},
+	}, g.Edges, "")
+}
+
+func TestModule(t *testing.T) {
+	a := &tf_docs.TFModule{Dir: "root/a", Title: "a", Link: "a", Modules: []*tf_docs.Module{{Name: "b", Source: "../b"}}}
+	b := &tf_docs.TFModule{Dir: "root/b", Title: "b", Link: "b", Modules: []*tf_docs.Module{{Name: "c", Source: "../c"}, {Name: "missing", Source: "../missing"}}}
+	c := &tf_docs.TFModule{Dir: "root/c", Title: "c", Link: "c"}
+	unrelated := &tf_docs.TFModule{Dir: "root/d", Title: "d", Link: "d", Modules: []*tf_docs.Module{{Name: "a", Source: "../a"}}}
+	modules := []*tf_docs.TFModule{a, b, c, unrelated}
+
+	g := Module(modules, a)
+	var ids []string
+	for _, node := range g.Nodes {
+		ids = append(ids, node.ID)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "external:root/missing"}, ids, "")
+	assert.Equal(t, []*Edge{
+		{From: "a", To: "b", Label: "b"},
+		{From: "b", To: "c", Label: "c"},
+		{From: "b", To: "external:root/missing", Label: "missing"},
+	}, g.Edges, "")
+}
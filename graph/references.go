@@ -0,0 +1,21 @@
+package graph
+
+import (
+	"github.com/nathmclean/tf_docs"
+)
+
+// References returns the reference graph of a module: a node for each object the module declares,
+// named by its address such as var.name or aws_vpc.main, and an edge from each object to every
+// object it references.
+func References(module *tf_docs.TFModule) *Graph {
+	g := &Graph{}
+
+	for _, address := range tf_docs.ModuleAddresses(module) {
+		g.AddNode(&Node{ID: address, Label: address})
+	}
+	for _, dependency := range module.Dependencies {
+		g.AddEdge(dependency.From, dependency.To, "")
+	}
+
+	return g
+}
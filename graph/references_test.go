@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferences(t *testing.T) {
+	module, err := tf_docs.ParseHCL2([]string{`variable "name" {}
+
+resource "aws_vpc" "main" {
+  tags = { Name = var.name }
+}
+
+output "id" {
+  value = aws_vpc.main.id
+}
+`}, "test")
+	assert.NoError(t, err, "")
+
+	var buf bytes.Buffer
+	assert.NoError(t, References(module).WriteMermaid(&buf), "")
+	assert.Equal(t, `graph LR
+  n0["var.name"]
+  n1["aws_vpc.main"]
+  n2["output.id"]
+  n1 --> n0
+  n2 --> n1
+`, buf.String(), "")
+}
@@ -0,0 +1,128 @@
+// Package lint checks the documentation of the modules found by tf_docs against a set of rules.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// Severity is how serious breaking a rule is.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+)
+
+// valid reports whether s is one of the known severities.
+func (s Severity) valid() bool {
+	return s == Error || s == Warning || s == Info
+}
+
+// Rule is a check run over each module.
+type Rule struct {
+	ID          string
+	Description string
+	// Severity is the severity of the rule's issues unless configured otherwise.
+	Severity Severity
+	Check    func(module *tf_docs.TFModule) []*tf_docs.Finding
+}
+
+// Issue is a Finding reported by a rule.
+type Issue struct {
+	*tf_docs.Finding
+	Rule     string
+	Severity Severity
+}
+
+func (i *Issue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Severity, i.Finding, i.Rule)
+}
+
+// RuleConfig configures a rule. The zero value leaves the rule enabled with its default severity.
+type RuleConfig struct {
+	Disabled bool
+	Severity Severity
+}
+
+// Config maps the ID of a rule to its configuration.
+type Config map[string]RuleConfig
+
+// Linter runs a set of rules over modules.
+type Linter struct {
+	rules []*Rule
+	// severities maps the ID of each enabled rule to its severity.
+	severities map[string]Severity
+}
+
+// New returns a Linter running the built-in rules as configured by config. It returns an error if
+// config refers to an unknown rule or severity.
+func New(config Config) (*Linter, error) {
+	return NewWithRules(Rules(), config)
+}
+
+// NewForConventions returns a Linter like New, for modules following conventions.
+func NewForConventions(conventions *tf_docs.Conventions, config Config) (*Linter, error) {
+	return NewWithRules(ConventionRules(conventions), config)
+}
+
+// NewWithRules returns a Linter running rules as configured by config.
+func NewWithRules(rules []*Rule, config Config) (*Linter, error) {
+	linter := &Linter{severities: map[string]Severity{}}
+
+	known := map[string]bool{}
+	for _, rule := range rules {
+		known[rule.ID] = true
+	}
+	var ids []string
+	for id := range config {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+		if severity := config[id].Severity; severity != "" && !severity.valid() {
+			return nil, fmt.Errorf("lint rule %q: unknown severity %q, expected error, warning or info", id, severity)
+		}
+	}
+
+	for _, rule := range rules {
+		c := config[rule.ID]
+		if c.Disabled {
+			continue
+		}
+		severity := rule.Severity
+		if c.Severity != "" {
+			severity = c.Severity
+		}
+		linter.rules = append(linter.rules, rule)
+		linter.severities[rule.ID] = severity
+	}
+
+	return linter, nil
+}
+
+// Lint runs every enabled rule over each module, returning the issues module by module, in the order
+// of the rules.
+func (l *Linter) Lint(modules []*tf_docs.TFModule) []*Issue {
+	var issues []*Issue
+
+	for _, module := range modules {
+		for _, rule := range l.rules {
+			for _, finding := range rule.Check(module) {
+				issues = append(issues, &Issue{
+					Finding:  finding,
+					Rule:     rule.ID,
+					Severity: l.severities[rule.ID],
+				})
+			}
+		}
+	}
+
+	return issues
+}
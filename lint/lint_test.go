@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithRules(t *testing.T) {
+	cases := []struct {
+		Config Config
+		Rules  []string
+		Err    bool
+	}{
+		{
+			Config: nil,
+			Rules:  []string{"a:error", "b:warning"},
+		},
+		{
+			Config: Config{"a": {Disabled: true}},
+			Rules:  []string{"b:warning"},
+		},
+		{
+			Config: Config{"b": {Severity: Info}},
+			Rules:  []string{"a:error", "b:info"},
+		},
+		{
+			Config: Config{"c": {Disabled: true}},
+			Err:    true,
+		},
+		{
+			Config: Config{"a": {Severity: "fatal"}},
+			Err:    true,
+		},
+	}
+
+	rules := []*Rule{
+		{ID: "a", Severity: Error},
+		{ID: "b", Severity: Warning},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("NewWithRules %v", i), func(t *testing.T) {
+			linter, err := NewWithRules(rules, c.Config)
+			if c.Err {
+				assert.Error(t, err, "Expected an error")
+				return
+			}
+			assert.NoError(t, err, "Expected no error")
+			var enabled []string
+			for _, rule := range linter.rules {
+				enabled = append(enabled, fmt.Sprintf("%s:%s", rule.ID, linter.severities[rule.ID]))
+			}
+			assert.Equal(t, c.Rules, enabled, "")
+		})
+	}
+}
+
+func TestLint(t *testing.T) {
+	rules := []*Rule{
+		{
+			ID:       "title",
+			Severity: Warning,
+			Check: func(module *tf_docs.TFModule) []*tf_docs.Finding {
+				return []*tf_docs.Finding{finding(module, tf_docs.Range{}, "module %s", module.Title)}
+			},
+		},
+	}
+	linter, err := NewWithRules(rules, nil)
+	assert.NoError(t, err, "")
+
+	var issues []string
+	for _, issue := range linter.Lint([]*tf_docs.TFModule{{Dir: "a", Title: "a"}, {Dir: "b", Title: "b"}}) {
+		issues = append(issues, issue.String())
+	}
+	assert.Equal(t, []string{
+		"warning: a: module a (title)",
+		"warning: b: module b (title)",
+	}, issues, "")
+}
@@ -0,0 +1,163 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// Rules returns the built-in rules for modules following the default conventions.
+func Rules() []*Rule {
+	return ConventionRules(nil)
+}
+
+// ConventionRules returns the built-in rules for modules following conventions, which decide the
+// comment that describes a module.
+func ConventionRules(conventions *tf_docs.Conventions) []*Rule {
+	firstComment := conventions != nil && conventions.Description == tf_docs.DescriptionFirstComment
+	descriptionComment := "a comment on the first line of a file starting with the module's name"
+	if firstComment {
+		descriptionComment = "a comment on the first line of a file"
+	}
+
+	return []*Rule{
+		{
+			ID:          "variable-description",
+			Description: "variables have a description",
+			Severity:    Error,
+			Check:       variableDescription,
+		},
+		{
+			ID:          "output-description",
+			Description: "outputs have a description",
+			Severity:    Error,
+			Check:       outputDescription,
+		},
+		{
+			ID:          "module-description",
+			Description: "the module has a description: " + descriptionComment,
+			Severity:    Warning,
+			Check:       moduleDescription(firstComment),
+		},
+		{
+			ID:          "lead-comment",
+			Description: "resources, data sources and module calls have a comment on the line before them",
+			Severity:    Warning,
+			Check:       leadComment,
+		},
+		{
+			ID:          "snake-case",
+			Description: "variables, locals, outputs, resources, data sources and module calls are named in snake_case",
+			Severity:    Error,
+			Check:       snakeCase,
+		},
+	}
+}
+
+func variableDescription(module *tf_docs.TFModule) []*tf_docs.Finding {
+	var findings []*tf_docs.Finding
+
+	for _, variable := range module.Variables {
+		if variable.Description == "" {
+			findings = append(findings, finding(module, variable.Range, "variable %q has no description", variable.Name))
+		}
+	}
+
+	return findings
+}
+
+func outputDescription(module *tf_docs.TFModule) []*tf_docs.Finding {
+	var findings []*tf_docs.Finding
+
+	for _, output := range module.Outputs {
+		if output.Description == "" {
+			findings = append(findings, finding(module, output.Range, "output %q has no description", output.Name))
+		}
+	}
+
+	return findings
+}
+
+// moduleDescription returns the check of the module-description rule, for descriptions that are the
+// first comment of a file when firstComment is set, and comments starting with the module's name
+// otherwise.
+func moduleDescription(firstComment bool) func(module *tf_docs.TFModule) []*tf_docs.Finding {
+	return func(module *tf_docs.TFModule) []*tf_docs.Finding {
+		if module.Description != "" {
+			return nil
+		}
+		if firstComment {
+			return []*tf_docs.Finding{
+				finding(module, tf_docs.Range{}, "module has no description, start a file with a comment"),
+			}
+		}
+		return []*tf_docs.Finding{
+			finding(module, tf_docs.Range{}, "module has no description, start a file with a comment beginning %q", module.Title),
+		}
+	}
+}
+
+func leadComment(module *tf_docs.TFModule) []*tf_docs.Finding {
+	var findings []*tf_docs.Finding
+
+	for _, resource := range module.Resources {
+		if resource.Description == "" {
+			findings = append(findings, finding(module, resource.Range, "resource %s.%s has no lead comment", resource.Type, resource.Name))
+		}
+	}
+	for _, dataSource := range module.DataSources {
+		if dataSource.Description == "" {
+			findings = append(findings, finding(module, dataSource.Range, "data source %s.%s has no lead comment", dataSource.Type, dataSource.Name))
+		}
+	}
+	for _, call := range module.Modules {
+		if call.Description == "" {
+			findings = append(findings, finding(module, call.Range, "module %q has no lead comment", call.Name))
+		}
+	}
+
+	return findings
+}
+
+// snakeCaseName matches names in snake_case, such as vpc_id.
+var snakeCaseName = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+func snakeCase(module *tf_docs.TFModule) []*tf_docs.Finding {
+	var findings []*tf_docs.Finding
+
+	check := func(kind, name string, r tf_docs.Range) {
+		if !snakeCaseName.MatchString(name) {
+			findings = append(findings, finding(module, r, "%s name %q is not snake_case", kind, name))
+		}
+	}
+	for _, variable := range module.Variables {
+		check("variable", variable.Name, variable.Range)
+	}
+	for _, local := range module.Locals {
+		check("local", local.Name, local.Range)
+	}
+	for _, resource := range module.Resources {
+		check("resource", resource.Name, resource.Range)
+	}
+	for _, dataSource := range module.DataSources {
+		check("data source", dataSource.Name, dataSource.Range)
+	}
+	for _, call := range module.Modules {
+		check("module", call.Name, call.Range)
+	}
+	for _, output := range module.Outputs {
+		check("output", output.Name, output.Range)
+	}
+
+	return findings
+}
+
+// finding returns a Finding within module.
+func finding(module *tf_docs.TFModule, r tf_docs.Range, format string, args ...interface{}) *tf_docs.Finding {
+	return &tf_docs.Finding{
+		Dir:     module.Dir,
+		Range:   r,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
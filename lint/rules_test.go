@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	cases := []struct {
+		Rule     string
+		Input    string
+		Messages []string
+	}{
+		{
+			Rule: "variable-description",
+			Input: `variable "documented" {
+  description = "a variable"
+}
+
+variable "undocumented" {}
+`,
+			Messages: []string{`<input>:5:1: variable "undocumented" has no description`},
+		},
+		{
+			Rule: "output-description",
+			Input: `output "undocumented" {
+  value = 1
+}
+`,
+			Messages: []string{`<input>:1:1: output "undocumented" has no description`},
+		},
+		{
+			Rule:     "module-description",
+			Input:    `# test describes itself`,
+			Messages: nil,
+		},
+		{
+			Rule:     "module-description",
+			Input:    `# something else`,
+			Messages: []string{`<input>: module has no description, start a file with a comment beginning "test"`},
+		},
+		{
+			Rule: "lead-comment",
+			Input: `# the bucket
+resource "aws_s3_bucket" "documented" {}
+
+resource "aws_s3_bucket" "undocumented" {}
+
+data "aws_ami" "ubuntu" {}
+
+module "vpc" {
+  source = "./vpc"
+}
+`,
+			Messages: []string{
+				`<input>:4:1: resource aws_s3_bucket.undocumented has no lead comment`,
+				`<input>:6:1: data source aws_ami.ubuntu has no lead comment`,
+				`<input>:8:1: module "vpc" has no lead comment`,
+			},
+		},
+		{
+			Rule: "snake-case",
+			Input: `variable "vpc_id" {}
+variable "vpcId" {}
+
+locals {
+  Name = "x"
+}
+
+resource "aws_s3_bucket" "my-bucket" {}
+
+output "arn_2" {
+  value = 1
+}
+`,
+			Messages: []string{
+				`<input>:2:1: variable name "vpcId" is not snake_case`,
+				`<input>:5:3: local name "Name" is not snake_case`,
+				`<input>:8:1: resource name "my-bucket" is not snake_case`,
+			},
+		},
+	}
+
+	rules := map[string]*Rule{}
+	for _, rule := range Rules() {
+		rules[rule.ID] = rule
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("%s %v", c.Rule, i), func(t *testing.T) {
+			module, err := tf_docs.ParseHCL2([]string{c.Input}, "test")
+			assert.NoError(t, err, "")
+
+			var messages []string
+			for _, finding := range rules[c.Rule].Check(module) {
+				messages = append(messages, finding.String())
+			}
+			assert.Equal(t, c.Messages, messages, "")
+		})
+	}
+}
+
+func TestConventionRules(t *testing.T) {
+	conventions := &tf_docs.Conventions{Description: tf_docs.DescriptionFirstComment}
+	rules := map[string]*Rule{}
+	for _, rule := range ConventionRules(conventions) {
+		rules[rule.ID] = rule
+	}
+	rule := rules["module-description"]
+	assert.Equal(t, "the module has a description: a comment on the first line of a file", rule.Description, "")
+
+	module, err := conventions.ParseHCL2Files([]*tf_docs.File{{Name: "main.tf", Body: `variable "x" {}`}}, "test")
+	assert.NoError(t, err, "")
+	var messages []string
+	for _, finding := range rule.Check(module) {
+		messages = append(messages, finding.String())
+	}
+	assert.Equal(t, []string{`<input>: module has no description, start a file with a comment`}, messages, "")
+
+	module, err = conventions.ParseHCL2Files([]*tf_docs.File{{Name: "main.tf", Body: `# something else`}}, "test")
+	assert.NoError(t, err, "")
+	assert.Empty(t, rule.Check(module), "")
+}
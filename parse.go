@@ -1,22 +1,38 @@
 package tf_docs
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
 	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 )
 
 type TFModule struct {
+	Dir         string
 	Path        string
 	Title       string
 	Link        string
 	Variables   []*Variable
 	Outputs     []*Output
 	Resources   []*Resource
+	DataSources []*DataSource
 	Modules     []*Module
+	Providers   []*Provider
+	Locals      []*Local
 	Description string
+
+	RequiredVersion string
+	// Dependencies are the references between the objects of the module, such as from a resource to
+	// the variables and locals it uses.
+	Dependencies []*Dependency
 }
 
 type Comment struct {
@@ -25,35 +41,114 @@ type Comment struct {
 	Line int
 }
 
+// File is the name and contents of a Terraform file.
+type File struct {
+	Name string
+	Body string
+}
+
+// Pos is a line and column within a Terraform file. Both start at 1.
+type Pos struct {
+	Line   int
+	Column int
+}
+
+// Range is where an element was declared: from the start of its block to its closing brace.
+type Range struct {
+	Filename string
+	Start    Pos
+	End      Pos
+}
+
 type Variable struct {
 	Name        string
 	Type        string
 	Description string
 	Default     string
 	Required    bool
+	Range       Range
+	// UsedBy are the addresses of the objects referencing the variable, such as aws_vpc.main or
+	// provider.aws.
+	UsedBy []string
 }
 
 type Output struct {
 	Description string
 	Name        string
+	Range       Range
+	References  []*Reference
+	// Exposes are the addresses of the objects of the module the output's value references.
+	Exposes []string
 }
 
 type Resource struct {
 	Type        string
 	Name        string
 	Description string
+	Range       Range
+	References  []*Reference
+}
+
+type DataSource struct {
+	Type        string
+	Name        string
+	Description string
+	Range       Range
+	References  []*Reference
 }
 
 type Module struct {
 	Name        string
 	Description string
 	Source      string
+	Version     string
+	Providers   map[string]string
+	Count       string
+	ForEach     string
+	DependsOn   []string
+	Inputs      map[string]string
+	Range       Range
+	References  []*Reference
+}
+
+// Local is a named value declared within a locals block.
+type Local struct {
+	Name       string
+	Range      Range
+	References []*Reference
 }
 
 type Value struct {
-	Key     map[string][]string
-	Val     map[string]string
-	Comment Comment
+	Key        map[string][]string
+	Val        map[string]string
+	Raw        map[string]string
+	Lists      map[string][]string
+	Blocks     []*Value
+	Attributes map[string]*Attribute
+	// HasDefault records whether the block sets a default attribute, whatever its value, as a default
+	// of "" cannot be told apart from no default by Val.
+	HasDefault bool
+	Comment    Comment
+	Range      Range
+	References []*Reference
+}
+
+// Attribute is where an attribute of a block was declared, from its name to the end of its value,
+// and the references its value makes.
+type Attribute struct {
+	Range      Range
+	References []*Reference
+}
+
+// moduleMetaArguments are the arguments of a module block that configure the call itself rather than
+// being passed to the module as inputs.
+var moduleMetaArguments = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"providers":  true,
+	"count":      true,
+	"for_each":   true,
+	"depends_on": true,
 }
 
 const (
@@ -61,78 +156,280 @@ const (
 	OUTPUT   = "output"
 	MODULE   = "module"
 	RESOURCE = "resource"
+	DATA     = "data"
+	LOCALS   = "locals"
 )
 
-// FindAndParse finds all of the modules within a directory and parses them all.
+// ErrNoModules is returned by FindAndParse when a directory contains no modules.
+var ErrNoModules = errors.New("no modules found")
+
+// ErrEmptyDirectory is returned by FindAndParse when it is given no directory to search.
+var ErrEmptyDirectory = errors.New("directory cannot be empty")
+
+// ParseFunc turns a module's Terraform files into a TFModule. ParseFiles (HCL1) and ParseHCL2Files
+// (HCL2 / Terraform 0.12+) are both ParseFuncs.
+type ParseFunc func(files []*File, moduleName string) (*TFModule, error)
+
+// Options controls how FindAndParseWithOptions discovers and parses modules.
+type Options struct {
+	// Parser parses the files of each module. Defaults to Conventions.ParseFiles.
+	Parser ParseFunc
+	// Conventions decide which files make up a module. Parser should follow the same conventions,
+	// such as by being Conventions.ParseHCL2Files.
+	Conventions *Conventions
+	// Include, when not empty, limits the modules to those whose directories match one of the
+	// patterns. Exclude skips the modules whose directories match one of its patterns. Patterns are
+	// filepath.Match patterns matched against each module directory, relative to the directory
+	// searched, and against each of its parent directories, so "examples" excludes
+	// "examples/complete" too.
+	Include []string
+	Exclude []string
+	// ContinueOnError records modules that cannot be read or parsed in Result.Failures rather than
+	// returning an error, so the modules that could be parsed can still be used.
+	ContinueOnError bool
+	// Concurrency is the maximum number of modules parsed at once. Defaults to GOMAXPROCS.
+	Concurrency int
+}
+
+// ModuleFailure records a module directory that could not be read or parsed.
+type ModuleFailure struct {
+	Dir string
+	Err error
+}
+
+// Result holds the modules found by FindAndParseWithOptions along with the module directories that
+// could not be read or parsed.
+type Result struct {
+	Modules  []*TFModule
+	Failures []*ModuleFailure
+}
+
+// FindAndParse finds all of the modules within a directory and parses them all. Modules that cannot
+// be parsed don't stop the others from being parsed, every problem found is returned as Errors.
 func FindAndParse(directory string) ([]*TFModule, error) {
-	var modules []*TFModule
+	result, err := FindAndParseWithOptions(directory, Options{})
+	return result.Modules, err
+}
 
-	directoryDepth := len(strings.Split(directory, "/"))
+// FindAndParseWithOptions finds all of the modules within a directory and parses them all using
+// the given Options. Unless Options.ContinueOnError is set, the errors of any module that failed are
+// returned as Errors.
+func FindAndParseWithOptions(directory string, opts Options) (*Result, error) {
+	return FindAndParseContext(context.Background(), directory, opts)
+}
+
+// FindAndParseContext behaves like FindAndParseWithOptions, stopping early with the context's error
+// if ctx is cancelled. Modules are parsed in parallel but are always returned in the same order.
+func FindAndParseContext(ctx context.Context, directory string, opts Options) (*Result, error) {
+	result := &Result{}
 
 	if directory == "" {
-		return modules, fmt.Errorf("directory cannot be empty")
+		return result, ErrEmptyDirectory
 	}
 
-	modulesDirs, err := traverseDirectory(directory)
+	modulesDirs, err := FindModuleDirs(directory, opts)
 	if err != nil {
-		return modules, err
+		return result, err
 	}
 	if len(modulesDirs) == 0 {
-		return modules, fmt.Errorf("no modules found in path %s", directory)
+		return result, fmt.Errorf("%w in path %s", ErrNoModules, directory)
 	}
 
-	for _, d := range modulesDirs {
-		var moduleFiles []string
-		files, err := ListModuleFiles(d)
-		if err != nil {
-			return modules, err
+	parsed, err := parseModuleDirs(ctx, directory, modulesDirs, opts)
+	if err != nil {
+		return result, err
+	}
+	for i, d := range modulesDirs {
+		if parsed[i].err != nil {
+			result.Failures = append(result.Failures, &ModuleFailure{Dir: d, Err: parsed[i].err})
+			continue
 		}
-		for _, file := range files {
-			fileBody, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", d, file))
-			if err != nil {
-				return modules, err
+		result.Modules = append(result.Modules, parsed[i].module)
+	}
+
+	if opts.ContinueOnError {
+		return result, nil
+	}
+	var errs Errors
+	for _, failure := range result.Failures {
+		errs.add(failure.Err)
+	}
+
+	return result, errs.err()
+}
+
+// parsedModule is the outcome of parsing a single module directory.
+type parsedModule struct {
+	module *TFModule
+	err    error
+}
+
+// parseModuleDirs parses each module directory using a pool of at most opts.Concurrency workers. The
+// outcome of parsing modulesDirs[i] is returned at index i.
+func parseModuleDirs(ctx context.Context, directory string, modulesDirs []string, opts Options) ([]parsedModule, error) {
+	parsed := make([]parsedModule, len(modulesDirs))
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	if concurrency > len(modulesDirs) {
+		concurrency = len(modulesDirs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				module, err := ParseModuleDir(directory, modulesDirs[i], opts)
+				parsed[i] = parsedModule{module: module, err: err}
 			}
-			moduleFiles = append(moduleFiles, string(fileBody))
+		}()
+	}
+
+feed:
+	for i := range modulesDirs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
 		}
-		splitModule := strings.Split(d, "/")
-		moduleName := splitModule[len(splitModule)-1]
-		tfFile, err := Parse(moduleFiles, moduleName)
+	}
+	close(jobs)
+	wg.Wait()
+
+	return parsed, ctx.Err()
+}
+
+// FindModuleDirs returns the module directories within a directory, those containing .tf files or
+// the files of opts.Conventions, in the order FindAndParseWithOptions returns their modules. Only
+// the directories allowed by opts.Include and opts.Exclude are returned.
+func FindModuleDirs(directory string, opts Options) ([]string, error) {
+	dirs, err := traverseDirectory(directory, opts.Conventions)
+	if err != nil {
+		return dirs, err
+	}
+	if len(opts.Include) == 0 && len(opts.Exclude) == 0 {
+		return dirs, nil
+	}
+
+	var included []string
+	for _, d := range dirs {
+		rel, err := filepath.Rel(directory, d)
 		if err != nil {
-			return modules, err
+			return nil, err
 		}
-		if len(splitModule) > directoryDepth + 1 {
-			tfFile.Path = strings.Join(splitModule[directoryDepth:len(splitModule)-1], "/")
+		if len(opts.Include) > 0 && !matchDir(opts.Include, rel) || matchDir(opts.Exclude, rel) {
+			continue
 		}
-		tfFile.Link = strings.Replace(tfFile.Path, "/", "-", -1) + "_" + tfFile.Title
-		if strings.HasPrefix(tfFile.Link, "_") {
-			tfFile.Link = strings.Replace(tfFile.Link, "_", "", 1)
+		included = append(included, d)
+	}
+	return included, nil
+}
+
+// matchDir reports whether the relative directory dir, or one of its parent directories, matches one
+// of the patterns. Invalid patterns never match; Options are checked by Validate.
+func matchDir(patterns []string, dir string) bool {
+	for d := dir; d != "." && d != string(filepath.Separator); d = filepath.Dir(d) {
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, d); ok {
+				return true
+			}
 		}
-		modules = append(modules, tfFile)
 	}
+	return false
+}
 
-	return modules, nil
+// Validate returns an error if the Options have invalid Conventions or Include or Exclude patterns.
+func (o Options) Validate() error {
+	if err := o.Conventions.Validate(); err != nil {
+		return err
+	}
+	for _, pattern := range append(append([]string(nil), o.Include...), o.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
 }
 
-// listModuleFiles returns a list ouf files with a .tf extension
-// within a directory.
-func ListModuleFiles(directory string) ([]string, error) {
-	files := []string{}
+// ParseModuleDir reads and parses the module in directory d, as returned by FindModuleDirs for the
+// root directory. The module's Path and Link are relative to the root directory, as they are when
+// parsed by FindAndParseWithOptions.
+func ParseModuleDir(directory, d string, opts Options) (*TFModule, error) {
+	parse := opts.Parser
+	if parse == nil {
+		parse = opts.Conventions.ParseFiles
+	}
+	directoryDepth := len(strings.Split(directory, "/"))
 
-	fileInfos, err := ioutil.ReadDir(directory)
+	var moduleFiles []*File
+	files, err := opts.Conventions.ListModuleFiles(d)
 	if err != nil {
-		return files, err
+		return nil, err
+	}
+	for _, file := range files {
+		fileBody, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", d, file))
+		if err != nil {
+			return nil, err
+		}
+		moduleFiles = append(moduleFiles, &File{Name: file, Body: string(fileBody)})
+	}
+	splitModule := strings.Split(d, "/")
+	moduleName := splitModule[len(splitModule)-1]
+	tfFile, err := parse(moduleFiles, moduleName)
+	if err != nil {
+		return nil, qualifyErrors(err, d)
+	}
+	tfFile.Dir = d
+	if len(splitModule) > directoryDepth+1 {
+		tfFile.Path = strings.Join(splitModule[directoryDepth:len(splitModule)-1], "/")
 	}
-	for _, i := range fileInfos {
-		if strings.HasSuffix(i.Name(), ".tf") {
-			files = append(files, i.Name())
+	tfFile.Link = strings.Replace(tfFile.Path, "/", "-", -1) + "_" + tfFile.Title
+	if strings.HasPrefix(tfFile.Link, "_") {
+		tfFile.Link = strings.Replace(tfFile.Link, "_", "", 1)
+	}
+
+	return tfFile, nil
+}
+
+// qualifyErrors prefixes the file names of any SyntaxError or SchemaError within err with the
+// directory of the module the file belongs to.
+func qualifyErrors(err error, directory string) error {
+	list, ok := err.(Errors)
+	if !ok {
+		list = Errors{err}
+	}
+	for _, e := range list {
+		switch e := e.(type) {
+		case *SyntaxError:
+			e.Filename = fmt.Sprintf("%s/%s", directory, e.Filename)
+		case *SchemaError:
+			e.Filename = fmt.Sprintf("%s/%s", directory, e.Filename)
 		}
 	}
+	return err
+}
 
-	return files, nil
+// listModuleFiles returns a list ouf files with a .tf extension
+// within a directory.
+func ListModuleFiles(directory string) ([]string, error) {
+	return (*Conventions)(nil).ListModuleFiles(directory)
+}
+
+// SkipDir reports whether a directory named name is left out when searching for modules: hidden
+// directories, such as .git and .terraform, where Terraform keeps the modules and providers it
+// downloads.
+func SkipDir(name string) bool {
+	return strings.HasPrefix(name, ".")
 }
 
-// traverseDirectory traverses directories and returns a list of directories that contain .tf files.
-func traverseDirectory(directory string) ([]string, error) {
+// traverseDirectory traverses directories and returns a list of directories that contain .tf files,
+// or the files of the conventions. Directories within it that SkipDir reports are not traversed.
+func traverseDirectory(directory string, conventions *Conventions) ([]string, error) {
 	var directoryPaths []string
 
 	fileInfos, err := ioutil.ReadDir(directory)
@@ -141,12 +438,12 @@ func traverseDirectory(directory string) ([]string, error) {
 	}
 
 	for _, fileInfo := range fileInfos {
-		if !fileInfo.IsDir() && strings.HasSuffix(fileInfo.Name(), ".tf") {
+		if !fileInfo.IsDir() && conventions.IsModuleFile(fileInfo.Name()) {
 			directoryPaths = append(directoryPaths, directory)
 			break
 		}
-		if fileInfo.IsDir() {
-			directories, err := traverseDirectory(fmt.Sprintf("%s/%s", directory, fileInfo.Name()))
+		if fileInfo.IsDir() && !SkipDir(fileInfo.Name()) {
+			directories, err := traverseDirectory(fmt.Sprintf("%s/%s", directory, fileInfo.Name()), conventions)
 			if err != nil {
 				return directoryPaths, nil
 			}
@@ -159,6 +456,17 @@ func traverseDirectory(directory string) ([]string, error) {
 
 // Parse generates a TFModule given a number of Terraform files (as strings) as input
 func Parse(hclText []string, moduleName string) (*TFModule, error) {
+	return ParseFiles(namelessFiles(hclText), moduleName)
+}
+
+// ParseFiles generates a TFModule given a number of Terraform files as input. The name of each file
+// is recorded in the Range of the elements declared within it.
+func ParseFiles(files []*File, moduleName string) (*TFModule, error) {
+	return parseFiles(files, moduleName, nil)
+}
+
+// parseFiles implements ParseFiles, following the conventions.
+func parseFiles(files []*File, moduleName string, conventions *Conventions) (*TFModule, error) {
 	result := &TFModule{}
 
 	if moduleName == "" {
@@ -168,58 +476,131 @@ func Parse(hclText []string, moduleName string) (*TFModule, error) {
 	result.Title = moduleName
 
 	var hclParseTrees []*ast.File
+	var errs Errors
 
-	for _, text := range hclText {
-		hclParseTree, err := hcl.Parse(text)
+	for _, file := range files {
+		hclParseTree, err := hcl.Parse(file.Body)
 		if err != nil {
-			return nil, err
+			errs.add(syntaxError(err, file.Name))
+			continue
 		}
 		hclParseTrees = append(hclParseTrees, hclParseTree)
 	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
 
 	var comments []*Comment
+	var values [][]*Value
+
+	for i, hclTree := range hclParseTrees {
+		comments = append(comments, extractComments(hclTree.Comments)...)
+		fileValues := extractValues(hclTree.Node)
+		setFilename(fileValues, files[i].Name)
+		values = append(values, fileValues)
+	}
+
+	if err := populate(result, comments, values, conventions); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// syntaxError converts an error returned by the HCL1 parser into a SyntaxError.
+func syntaxError(err error, filename string) *SyntaxError {
+	if posErr, ok := err.(*parser.PosError); ok {
+		return &SyntaxError{
+			Filename: filename,
+			Line:     posErr.Pos.Line,
+			Column:   posErr.Pos.Column,
+			Message:  posErr.Err.Error(),
+		}
+	}
+	return &SyntaxError{Filename: filename, Message: err.Error()}
+}
+
+// namelessFiles wraps the contents of Terraform files, whose names are unknown, as Files.
+func namelessFiles(hclText []string) []*File {
+	var files []*File
+	for _, text := range hclText {
+		files = append(files, &File{Body: text})
+	}
+	return files
+}
+
+// populate fills in a TFModule from the comments and the values of each of the module's files,
+// following the conventions. Every block of a type the conventions extract that cannot be extracted
+// is reported in the returned Errors.
+func populate(result *TFModule, comments []*Comment, fileValues [][]*Value, conventions *Conventions) error {
 	var variables []*Variable
 	var outputs []*Output
 	var modules []*Module
 	var resources []*Resource
+	var dataSources []*DataSource
+	var locals []*Local
+	var errs Errors
 
-	for _, hclTree := range hclParseTrees {
-		comments = append(comments, extractComments(hclTree.Comments)...)
-		values := extractValues(hclTree.Node)
-
+	for _, values := range fileValues {
 		tmpVariables, err := extractVariables(values)
-		if err != nil {
-			return result, err
+		if err != nil && conventions.extracts(VARIABLE) {
+			errs.add(err)
 		}
 		variables = append(variables, tmpVariables...)
 
 		tmpOutputs, err := extractOutputs(values)
-		if err != nil {
-			return result, err
+		if err != nil && conventions.extracts(OUTPUT) {
+			errs.add(err)
 		}
 		outputs = append(outputs, tmpOutputs...)
 
 		tmpModules, err := extractModules(values)
-		if err != nil {
-			return result, err
+		if err != nil && conventions.extracts(MODULE) {
+			errs.add(err)
 		}
 		modules = append(modules, tmpModules...)
 
 		tmpResources, err := extractResources(values)
-		if err != nil {
-			return result, err
+		if err != nil && conventions.extracts(RESOURCE) {
+			errs.add(err)
 		}
 		resources = append(resources, tmpResources...)
+
+		tmpDataSources, err := extractDataSources(values)
+		if err != nil && conventions.extracts(DATA) {
+			errs.add(err)
+		}
+		dataSources = append(dataSources, tmpDataSources...)
+
+		locals = append(locals, extractLocals(values)...)
 	}
-	description := extractDescription(comments, moduleName)
+	description := conventions.description(comments, result.Title)
 
 	result.Variables = variables
 	result.Outputs = outputs
 	result.Description = description
 	result.Modules = modules
 	result.Resources = resources
+	result.DataSources = dataSources
+	result.Locals = locals
 
-	return result, nil
+	var allValues []*Value
+	for _, values := range fileValues {
+		allValues = append(allValues, values...)
+	}
+	providers, requiredVersion, err := extractProviders(allValues)
+	if err != nil && conventions.extracts(PROVIDER) {
+		errs.add(err)
+	}
+	result.Providers = providers
+	result.RequiredVersion = requiredVersion
+	result.Dependencies = extractDependencies(result)
+	trackUsage(result)
+	// Usage is tracked across every block, so that a variable used only by blocks that are not
+	// extracted is still used.
+	conventions.filter(result)
+
+	return errs.err()
 }
 
 // extractDescription parse each comment and returns the first comment that starts with the moduleName
@@ -243,16 +624,20 @@ func extractDescription(comments []*Comment, moduleName string) string {
 
 // extractModule iterates over each Value, selects those that are modules and returns a slice of
 // Modules generated from the matching values. Checks that the module is names and has a source.
+// Arguments other than the meta-arguments are recorded as the module's inputs, as written.
 func extractModules(values []*Value) ([]*Module, error) {
 	var modules []*Module
+	var errs Errors
 
 	mods := extractElement(values, MODULE)
 	for _, m := range mods {
-		if _, ok := m.Key["module"]; !ok {
-			return modules, fmt.Errorf("name is required for a module")
+		if len(m.Key["module"]) == 0 {
+			errs.add(newSchemaError(m, MODULE, "name is required for a module"))
+			continue
 		}
 		if _, ok := m.Val["source"]; !ok {
-			return modules, fmt.Errorf("source is required for a variable")
+			errs.add(newSchemaError(m, MODULE, "source is required for a module"))
+			continue
 		}
 
 		module := &Module{}
@@ -260,22 +645,45 @@ func extractModules(values []*Value) ([]*Module, error) {
 		module.Name = name[0]
 		module.Description = m.Comment.Text
 		module.Source = m.Val["source"]
+		module.Version = m.Val["version"]
+		module.Count = m.Raw["count"]
+		module.ForEach = m.Raw["for_each"]
+		module.DependsOn = m.Lists["depends_on"]
+		module.Range = m.Range
+		module.References = m.References
+
+		for _, block := range m.Blocks {
+			if _, ok := block.Key["providers"]; ok {
+				module.Providers = block.Val
+			}
+		}
+		for argument, expression := range m.Raw {
+			if moduleMetaArguments[argument] {
+				continue
+			}
+			if module.Inputs == nil {
+				module.Inputs = map[string]string{}
+			}
+			module.Inputs[argument] = expression
+		}
 
 		modules = append(modules, module)
 	}
 
-	return modules, nil
+	return modules, errs.err()
 }
 
 // extractResources iterates over each value, selects those that are resources and returns a slice of
 // Resources generated from those matching values. Checks that the Resource has a name.
 func extractResources(values []*Value) ([]*Resource, error) {
 	var resources []*Resource
+	var errs Errors
 
 	mods := extractElement(values, RESOURCE)
 	for _, m := range mods {
-		if _, ok := m.Key["resource"]; !ok {
-			return resources, fmt.Errorf("name is required for a resource")
+		if len(m.Key["resource"]) != 2 {
+			errs.add(newSchemaError(m, RESOURCE, "type and name are required for a resource"))
+			continue
 		}
 
 		resource := &Resource{}
@@ -283,27 +691,90 @@ func extractResources(values []*Value) ([]*Resource, error) {
 		resource.Name = keys[1]
 		resource.Type = keys[0]
 		resource.Description = m.Comment.Text
+		resource.Range = m.Range
+		resource.References = m.References
 
 		resources = append(resources, resource)
 	}
 
-	return resources, nil
+	return resources, errs.err()
+}
+
+// extractLocals iterates over each value, selects the locals blocks and returns a Local for each of
+// their attributes, in the order they were declared.
+func extractLocals(values []*Value) []*Local {
+	var locals []*Local
+
+	for _, l := range extractElement(values, LOCALS) {
+		var blockLocals []*Local
+		for name, attribute := range l.Attributes {
+			blockLocals = append(blockLocals, &Local{
+				Name:       name,
+				Range:      attribute.Range,
+				References: attribute.References,
+			})
+		}
+		sort.Slice(blockLocals, func(i, j int) bool {
+			a, b := blockLocals[i].Range.Start, blockLocals[j].Range.Start
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			if a.Column != b.Column {
+				return a.Column < b.Column
+			}
+			return blockLocals[i].Name < blockLocals[j].Name
+		})
+		locals = append(locals, blockLocals...)
+	}
+
+	return locals
+}
+
+// extractDataSources iterates over each value, selects those that are data sources and returns a slice
+// of DataSources generated from those matching values. Checks that the DataSource has a type and name.
+func extractDataSources(values []*Value) ([]*DataSource, error) {
+	var dataSources []*DataSource
+	var errs Errors
+
+	data := extractElement(values, DATA)
+	for _, d := range data {
+		if len(d.Key["data"]) != 2 {
+			errs.add(newSchemaError(d, DATA, "type and name are required for a data source"))
+			continue
+		}
+
+		dataSource := &DataSource{}
+		keys := d.Key["data"]
+		dataSource.Name = keys[1]
+		dataSource.Type = keys[0]
+		dataSource.Description = d.Comment.Text
+		dataSource.Range = d.Range
+		dataSource.References = d.References
+
+		dataSources = append(dataSources, dataSource)
+	}
+
+	return dataSources, errs.err()
 }
 
 // extractOutputs iterates over each value, selects those that are outputs and returns a slice of
 // Outputs generated from those matching values. Checks that the Output has a name.
 func extractOutputs(values []*Value) ([]*Output, error) {
 	var outputs []*Output
+	var errs Errors
 
 	outs := extractElement(values, OUTPUT)
 	for _, o := range outs {
-		if _, ok := o.Key["output"]; !ok {
-			return outputs, fmt.Errorf("name is required for a output")
+		if len(o.Key["output"]) == 0 {
+			errs.add(newSchemaError(o, OUTPUT, "name is required for an output"))
+			continue
 		}
 		output := &Output{}
 
 		name := o.Key["output"]
 		output.Name = name[0]
+		output.Range = o.Range
+		output.References = o.References
 		if _, ok := o.Val["description"]; ok {
 			output.Description = o.Val["description"]
 		}
@@ -311,21 +782,24 @@ func extractOutputs(values []*Value) ([]*Output, error) {
 		outputs = append(outputs, output)
 	}
 
-	return outputs, nil
+	return outputs, errs.err()
 }
 
 // extractVariables iterates over each value, selects those that are variables and returns a slice of
 // Variables generated from those matching values. Checks that the Variable has a name and a type.
 func extractVariables(values []*Value) ([]*Variable, error) {
 	var variables []*Variable
+	var errs Errors
 
 	vars := extractElement(values, VARIABLE)
 	for _, v := range vars {
-		if _, ok := v.Key["variable"]; !ok {
-			return variables, fmt.Errorf("name is required for a variable")
+		if len(v.Key["variable"]) == 0 {
+			errs.add(newSchemaError(v, VARIABLE, "name is required for a variable"))
+			continue
 		}
 		if _, ok := v.Val["type"]; !ok {
-			return variables, fmt.Errorf("type is required for a variable")
+			errs.add(newSchemaError(v, VARIABLE, "type is required for a variable"))
+			continue
 		}
 
 		variable := &Variable{}
@@ -333,20 +807,19 @@ func extractVariables(values []*Value) ([]*Variable, error) {
 		name := v.Key["variable"]
 		variable.Name = name[0]
 		variable.Type = v.Val["type"]
+		variable.Range = v.Range
 		if _, ok := v.Val["description"]; ok {
 			variable.Description = v.Val["description"]
 		}
 		if _, ok := v.Val["default"]; ok {
 			variable.Default = v.Val["default"]
 		}
-		if variable.Default == "" {
-			variable.Required = true
-		}
+		variable.Required = !v.HasDefault
 
 		variables = append(variables, variable)
 	}
 
-	return variables, nil
+	return variables, errs.err()
 }
 
 // extractElement returns all the Values, from a list of Values, that have a specified key.
@@ -372,12 +845,9 @@ func extractValues(node ast.Node) []*Value {
 		value := &Value{}
 		switch item.Val.(type) {
 		case *ast.ObjectType:
-			val := parseValues(item.Val.(*ast.ObjectType))
-			key := parseKeys(item.Keys)
+			value = parseObject(item, item.Val.(*ast.ObjectType))
 			comment, _ := parseComment(item.LeadComment)
 
-			value.Val = val
-			value.Key = key
 			value.Comment = comment
 		}
 
@@ -387,12 +857,64 @@ func extractValues(node ast.Node) []*Value {
 	return values
 }
 
+// parseObject returns the Value of an item whose value is an object: its keys, values, nested blocks
+// and position.
+func parseObject(item *ast.ObjectItem, object *ast.ObjectType) *Value {
+	attributes := parseAttributes(object)
+	_, hasDefault := attributes["default"]
+	return &Value{
+		Key:        parseKeys(item.Keys),
+		Val:        parseValues(object),
+		Raw:        parseRaw(object),
+		Lists:      parseLists(object),
+		Blocks:     parseBlocks(object),
+		Attributes: attributes,
+		HasDefault: hasDefault,
+		Range: Range{
+			Start: Pos{Line: item.Pos().Line, Column: item.Pos().Column},
+			End:   Pos{Line: object.Rbrace.Line, Column: object.Rbrace.Column},
+		},
+		References: parseReferences(object),
+	}
+}
+
+// parseBlocks returns a Value for each block, or object, nested within an object.
+func parseBlocks(rawValue *ast.ObjectType) []*Value {
+	var blocks []*Value
+
+	for _, item := range rawValue.List.Items {
+		if object, ok := item.Val.(*ast.ObjectType); ok {
+			blocks = append(blocks, parseObject(item, object))
+		}
+	}
+
+	return blocks
+}
+
+// setFilename records the name of the file that values, the blocks nested within them and the
+// references they make, were declared in.
+func setFilename(values []*Value, filename string) {
+	for _, value := range values {
+		value.Range.Filename = filename
+		for _, reference := range value.References {
+			reference.Range.Filename = filename
+		}
+		for _, attribute := range value.Attributes {
+			attribute.Range.Filename = filename
+			for _, reference := range attribute.References {
+				reference.Range.Filename = filename
+			}
+		}
+		setFilename(value.Blocks, filename)
+	}
+}
+
 // parseKeys returns the first key from a slice of *ast.ObjectKey mapped to a slice of strings consisting
 // of values for that key
 func parseKeys(rawKeys []*ast.ObjectKey) map[string][]string {
 	result := map[string][]string{}
 
-	key := rawKeys[0].Token.Text
+	key := trimStrings(rawKeys[0].Token.Text)
 	values := []string{}
 	for i := 1; i < len(rawKeys); i++ {
 		values = append(values, trimStrings(rawKeys[i].Token.Text))
@@ -427,6 +949,102 @@ func parseValues(rawValue *ast.ObjectType) map[string]string {
 	return result
 }
 
+// parseRaw returns each attribute of an object mapped to its value as it was written, such as
+// "\"value\"" or "[\"a\", \"b\"]".
+func parseRaw(rawValue *ast.ObjectType) map[string]string {
+	var result map[string]string
+
+	for _, item := range rawValue.List.Items {
+		if result == nil {
+			result = map[string]string{}
+		}
+		result[trimStrings(item.Keys[0].Token.Text)] = rawNode(item.Val)
+	}
+
+	return result
+}
+
+// parseAttributes returns each attribute of an object mapped to its position and the references made
+// by its value.
+func parseAttributes(rawValue *ast.ObjectType) map[string]*Attribute {
+	var result map[string]*Attribute
+
+	for _, item := range rawValue.List.Items {
+		if result == nil {
+			result = map[string]*Attribute{}
+		}
+		result[trimStrings(item.Keys[0].Token.Text)] = &Attribute{
+			Range: Range{
+				Start: Pos{Line: item.Pos().Line, Column: item.Pos().Column},
+				End:   nodeEnd(item.Val),
+			},
+			References: parseReferences(item.Val),
+		}
+	}
+
+	return result
+}
+
+// nodeEnd returns the position at which an HCL1 value ends.
+func nodeEnd(node ast.Node) Pos {
+	switch n := node.(type) {
+	case *ast.LiteralType:
+		lines := strings.Split(n.Token.Text, "\n")
+		if len(lines) == 1 {
+			return Pos{Line: n.Token.Pos.Line, Column: n.Token.Pos.Column + len(n.Token.Text)}
+		}
+		return Pos{Line: n.Token.Pos.Line + len(lines) - 1, Column: len(lines[len(lines)-1]) + 1}
+	case *ast.ListType:
+		return Pos{Line: n.Rbrack.Line, Column: n.Rbrack.Column + 1}
+	case *ast.ObjectType:
+		return Pos{Line: n.Rbrace.Line, Column: n.Rbrace.Column + 1}
+	}
+	return Pos{Line: node.Pos().Line, Column: node.Pos().Column}
+}
+
+// parseLists returns each attribute of an object whose value is a list mapped to the list's items.
+func parseLists(rawValue *ast.ObjectType) map[string][]string {
+	var result map[string][]string
+
+	for _, item := range rawValue.List.Items {
+		list, ok := item.Val.(*ast.ListType)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = map[string][]string{}
+		}
+		var valueList []string
+		for _, v := range list.List {
+			valueList = append(valueList, trimStrings(rawNode(v)))
+		}
+		result[trimStrings(item.Keys[0].Token.Text)] = valueList
+	}
+
+	return result
+}
+
+// rawNode rebuilds the text of a value from its ast.Node.
+func rawNode(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.LiteralType:
+		return n.Token.Text
+	case *ast.ListType:
+		var items []string
+		for _, v := range n.List {
+			items = append(items, rawNode(v))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+	case *ast.ObjectType:
+		var items []string
+		for _, item := range n.List.Items {
+			items = append(items, fmt.Sprintf("%s = %s", item.Keys[0].Token.Text, rawNode(item.Val)))
+		}
+		return fmt.Sprintf("{%s}", strings.Join(items, ", "))
+	}
+	return ""
+}
+
 func extractComments(commentGroup []*ast.CommentGroup) []*Comment {
 	var comments []*Comment
 
@@ -447,6 +1065,9 @@ func tidyComment(comment string) string {
 	if strings.HasPrefix(comment, "//") {
 		result = strings.TrimPrefix(result, "//")
 	}
+	if strings.HasPrefix(comment, "#") {
+		result = strings.TrimPrefix(result, "#")
+	}
 	if strings.HasPrefix(comment, "/*") {
 		result = strings.TrimPrefix(result, "/*")
 		result = strings.TrimSuffix(result, "*/")
@@ -0,0 +1,283 @@
+package tf_docs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ParseHCL2 generates a TFModule given a number of Terraform files (as strings) written using
+// HCL2 / Terraform 0.12+ syntax. It fills the same structures as Parse.
+func ParseHCL2(hclText []string, moduleName string) (*TFModule, error) {
+	return ParseHCL2Files(namelessFiles(hclText), moduleName)
+}
+
+// ParseHCL2Files generates a TFModule given a number of Terraform files written using HCL2 /
+// Terraform 0.12+ syntax. It fills the same structures as ParseFiles.
+func ParseHCL2Files(files []*File, moduleName string) (*TFModule, error) {
+	return parseHCL2Files(files, moduleName, nil)
+}
+
+// parseHCL2Files implements ParseHCL2Files, following the conventions.
+func parseHCL2Files(files []*File, moduleName string, conventions *Conventions) (*TFModule, error) {
+	result := &TFModule{}
+
+	if moduleName == "" {
+		return result, fmt.Errorf("error moduleName cannot be empty")
+	}
+
+	result.Title = moduleName
+
+	var comments []*Comment
+	var values [][]*Value
+
+	var errs Errors
+
+	for _, f := range files {
+		src := []byte(f.Body)
+		file, diags := hclsyntax.ParseConfig(src, f.Name, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			errs.add(diagnosticErrors(diags))
+			continue
+		}
+		tokens, diags := hclsyntax.LexConfig(src, f.Name, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			errs.add(diagnosticErrors(diags))
+			continue
+		}
+
+		fileComments, leadComments := extractHCL2Comments(tokens)
+		comments = append(comments, fileComments...)
+		values = append(values, extractHCL2Values(file.Body.(*hclsyntax.Body), src, leadComments))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if err := populate(result, comments, values, conventions); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// extractHCL2Values returns a Value for each top level block in an HCL2 body, in the same shape as
+// extractValues produces for HCL1. leadComments maps the line a comment group ends on to the group.
+func extractHCL2Values(body *hclsyntax.Body, src []byte, leadComments map[int]*Comment) []*Value {
+	var values []*Value
+
+	for _, block := range body.Blocks {
+		value := hcl2BlockValue(block, src)
+		// Terraform 0.12+ treats a variable without a type constraint as "any".
+		if _, ok := value.Val["type"]; block.Type == VARIABLE && !ok {
+			value.Val["type"] = "any"
+		}
+		if comment, ok := leadComments[block.TypeRange.Start.Line-1]; ok {
+			value.Comment = *comment
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// hcl2BlockValue returns the Value of a block. Nested blocks, and attributes whose value is an object
+// such as the entries of required_providers, become the Value's Blocks as they do with HCL1.
+func hcl2BlockValue(block *hclsyntax.Block, src []byte) *Value {
+	value := &Value{
+		Key: map[string][]string{block.Type: block.Labels},
+		Val: map[string]string{},
+		Range: Range{
+			Filename: block.TypeRange.Filename,
+			Start:    Pos{Line: block.TypeRange.Start.Line, Column: block.TypeRange.Start.Column},
+			End:      Pos{Line: block.CloseBraceRange.Start.Line, Column: block.CloseBraceRange.Start.Column},
+		},
+	}
+
+	for _, attr := range sortedAttributes(block.Body) {
+		value.Val[attr.Name] = hcl2ExprString(attr.Expr, src)
+		if value.Raw == nil {
+			value.Raw = map[string]string{}
+		}
+		value.Raw[attr.Name] = string(attr.Expr.Range().SliceBytes(src))
+		if value.Attributes == nil {
+			value.Attributes = map[string]*Attribute{}
+		}
+		attribute := &Attribute{
+			Range: Range{
+				Filename: attr.SrcRange.Filename,
+				Start:    Pos{Line: attr.SrcRange.Start.Line, Column: attr.SrcRange.Start.Column},
+				End:      Pos{Line: attr.SrcRange.End.Line, Column: attr.SrcRange.End.Column},
+			},
+		}
+		for _, traversal := range attr.Expr.Variables() {
+			attribute.References = append(attribute.References, traversalReference(traversal))
+		}
+		value.Attributes[attr.Name] = attribute
+		if attr.Name == "default" {
+			value.HasDefault = true
+		}
+		if tuple, ok := attr.Expr.(*hclsyntax.TupleConsExpr); ok {
+			if value.Lists == nil {
+				value.Lists = map[string][]string{}
+			}
+			var items []string
+			for _, item := range tuple.Exprs {
+				items = append(items, hcl2ExprString(item, src))
+			}
+			value.Lists[attr.Name] = items
+		}
+		if object, ok := attr.Expr.(*hclsyntax.ObjectConsExpr); ok {
+			value.Blocks = append(value.Blocks, hcl2ObjectValue(attr, object, src))
+		}
+	}
+	for _, nested := range block.Body.Blocks {
+		value.Blocks = append(value.Blocks, hcl2BlockValue(nested, src))
+	}
+	value.References = hcl2References(block.Body)
+
+	return value
+}
+
+// hcl2ObjectValue returns the Value of an attribute whose value is an object.
+func hcl2ObjectValue(attr *hclsyntax.Attribute, object *hclsyntax.ObjectConsExpr, src []byte) *Value {
+	value := &Value{
+		Key: map[string][]string{attr.Name: {}},
+		Val: map[string]string{},
+		Range: Range{
+			Filename: attr.SrcRange.Filename,
+			Start:    Pos{Line: attr.SrcRange.Start.Line, Column: attr.SrcRange.Start.Column},
+			End:      Pos{Line: object.SrcRange.End.Line, Column: object.SrcRange.End.Column - 1},
+		},
+	}
+
+	for _, item := range object.Items {
+		key := hcl.ExprAsKeyword(item.KeyExpr)
+		if key == "" {
+			key = hcl2ExprString(item.KeyExpr, src)
+		}
+		value.Val[key] = hcl2ExprString(item.ValueExpr, src)
+	}
+
+	return value
+}
+
+// sortedAttributes returns the attributes of a body in the order they were written.
+func sortedAttributes(body *hclsyntax.Body) []*hclsyntax.Attribute {
+	var attrs []*hclsyntax.Attribute
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+	return attrs
+}
+
+// hcl2ExprString renders an expression as a string. Constant values are rendered the same way
+// parseValues renders HCL1 literals, anything else (types, references, function calls) is returned
+// as it was written in the source.
+func hcl2ExprString(expr hclsyntax.Expression, src []byte) string {
+	if val, diags := expr.Value(nil); !diags.HasErrors() {
+		if s, ok := ctyString(val); ok {
+			return s
+		}
+	}
+
+	return trimStrings(string(expr.Range().SliceBytes(src)))
+}
+
+// ctyString renders a primitive value, or a list of primitive values, as a string.
+func ctyString(val cty.Value) (string, bool) {
+	if !val.IsWhollyKnown() {
+		return "", false
+	}
+	if val.IsNull() {
+		return "null", true
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return val.AsString(), true
+	case ty == cty.Number:
+		return val.AsBigFloat().Text('f', -1), true
+	case ty == cty.Bool:
+		if val.True() {
+			return "true", true
+		}
+		return "false", true
+	case ty.IsListType() || ty.IsTupleType() || ty.IsSetType():
+		var valueList []string
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			s, ok := ctyString(v)
+			if !ok {
+				return "", false
+			}
+			valueList = append(valueList, s)
+		}
+		return fmt.Sprintf("[%s]", strings.Join(valueList, ", ")), true
+	}
+
+	return "", false
+}
+
+// extractHCL2Comments groups the comments in a token stream the way the HCL1 parser does, joining
+// comments on consecutive lines. Comments trailing code on the same line are ignored. It returns the
+// groups along with a map of the line each group ends on, used to find lead comments.
+func extractHCL2Comments(tokens hclsyntax.Tokens) ([]*Comment, map[int]*Comment) {
+	var comments []*Comment
+	leadComments := map[int]*Comment{}
+
+	var group []string
+	var start hcl.Pos
+	lastLine, codeLine := 0, 0
+
+	flush := func() {
+		if len(group) == 0 {
+			return
+		}
+		comment := &Comment{
+			Text: strings.Join(group, " "),
+			Col:  start.Column,
+			Line: start.Line,
+		}
+		comments = append(comments, comment)
+		leadComments[lastLine] = comment
+		group = nil
+	}
+
+	for _, tok := range tokens {
+		switch tok.Type {
+		case hclsyntax.TokenComment:
+			if tok.Range.Start.Line == codeLine {
+				continue
+			}
+			if len(group) > 0 && tok.Range.Start.Line != lastLine+1 {
+				flush()
+			}
+			if len(group) == 0 {
+				start = tok.Range.Start
+			}
+			group = append(group, tidyComment(strings.TrimSpace(string(tok.Bytes))))
+			lastLine = tok.Range.End.Line
+			// Line comments include their trailing newline.
+			if strings.HasSuffix(string(tok.Bytes), "\n") {
+				lastLine--
+			}
+		case hclsyntax.TokenNewline, hclsyntax.TokenEOF:
+		default:
+			flush()
+			codeLine = tok.Range.End.Line
+		}
+	}
+	flush()
+
+	return comments, leadComments
+}
@@ -0,0 +1,370 @@
+package tf_docs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestParseHCL2(t *testing.T) {
+	cases := []struct {
+		Input      []string
+		ModuleName string
+		Module     *TFModule
+		Err        bool
+	}{
+		{
+			Input: []string{
+				`# test does modern things
+
+variable "name" {
+  type        = string
+  description = "desc"
+}
+
+variable "tags" {
+  type = map(string)
+  default = {
+    Name = "test"
+  }
+}
+
+variable "zones" {
+  default = ["a", "b"]
+}
+
+# module that does a thing
+module "test" {
+  source = "../../test"
+  name   = var.name
+}
+
+// resource desc
+// over two lines
+resource "aws" "test" {
+  k = "v" # not a lead comment
+  for_each = { for k, v in var.tags : k => v }
+}
+
+output "val" {
+  value       = aws.test.k
+  description = "output desc"
+}
+`,
+			},
+			ModuleName: "test",
+			Module: &TFModule{
+				Title: "test",
+				Variables: []*Variable{
+					{
+						Name:        "name",
+						Type:        "string",
+						Description: "desc",
+						Required:    true,
+						Range: Range{
+							Start: Pos{Line: 3, Column: 1},
+							End:   Pos{Line: 6, Column: 1},
+						},
+						UsedBy: []string{"module.test"},
+					},
+					{
+						Name:    "tags",
+						Type:    "map(string)",
+						Default: "{\n    Name = \"test\"\n  }",
+						Range: Range{
+							Start: Pos{Line: 8, Column: 1},
+							End:   Pos{Line: 13, Column: 1},
+						},
+						UsedBy: []string{"aws.test"},
+					},
+					{
+						Name:    "zones",
+						Type:    "any",
+						Default: "[a, b]",
+						Range: Range{
+							Start: Pos{Line: 15, Column: 1},
+							End:   Pos{Line: 17, Column: 1},
+						},
+					},
+				},
+				Outputs: []*Output{
+					{
+						Description: "output desc",
+						Name:        "val",
+						Range: Range{
+							Start: Pos{Line: 32, Column: 1},
+							End:   Pos{Line: 35, Column: 1},
+						},
+						References: []*Reference{
+							{
+								Name: "aws.test.k",
+								Range: Range{
+									Start: Pos{Line: 33, Column: 17},
+									End:   Pos{Line: 33, Column: 27},
+								},
+							},
+						},
+						Exposes: []string{"aws.test"},
+					},
+				},
+				Resources: []*Resource{
+					{
+						Type:        "aws",
+						Name:        "test",
+						Description: "resource desc over two lines",
+						Range: Range{
+							Start: Pos{Line: 27, Column: 1},
+							End:   Pos{Line: 30, Column: 1},
+						},
+						References: []*Reference{
+							{
+								Name: "var.tags",
+								Range: Range{
+									Start: Pos{Line: 29, Column: 28},
+									End:   Pos{Line: 29, Column: 36},
+								},
+							},
+						},
+					},
+				},
+				Modules: []*Module{
+					{
+						Name:        "test",
+						Description: "module that does a thing",
+						Source:      "../../test",
+						Inputs: map[string]string{
+							"name": "var.name",
+						},
+						Range: Range{
+							Start: Pos{Line: 20, Column: 1},
+							End:   Pos{Line: 23, Column: 1},
+						},
+						References: []*Reference{
+							{
+								Name: "var.name",
+								Range: Range{
+									Start: Pos{Line: 22, Column: 12},
+									End:   Pos{Line: 22, Column: 20},
+								},
+							},
+						},
+					},
+				},
+				Description: "test does modern things",
+				Dependencies: []*Dependency{
+					{
+						From: "aws.test",
+						To:   "var.tags",
+						Range: Range{
+							Start: Pos{Line: 29, Column: 28},
+							End:   Pos{Line: 29, Column: 36},
+						},
+					},
+					{
+						From: "module.test",
+						To:   "var.name",
+						Range: Range{
+							Start: Pos{Line: 22, Column: 12},
+							End:   Pos{Line: 22, Column: 20},
+						},
+					},
+					{
+						From: "output.val",
+						To:   "aws.test",
+						Range: Range{
+							Start: Pos{Line: 33, Column: 17},
+							End:   Pos{Line: 33, Column: 27},
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: []string{
+				`locals {
+  prefix = "app"
+  name   = "${local.prefix}-${path.module}"
+}
+`,
+			},
+			ModuleName: "test",
+			Module: &TFModule{
+				Title: "test",
+				Locals: []*Local{
+					{
+						Name: "prefix",
+						Range: Range{
+							Start: Pos{Line: 2, Column: 3},
+							End:   Pos{Line: 2, Column: 17},
+						},
+					},
+					{
+						Name: "name",
+						Range: Range{
+							Start: Pos{Line: 3, Column: 3},
+							End:   Pos{Line: 3, Column: 44},
+						},
+						References: []*Reference{
+							{
+								Name: "local.prefix",
+								Range: Range{
+									Start: Pos{Line: 3, Column: 15},
+									End:   Pos{Line: 3, Column: 27},
+								},
+							},
+							{
+								Name: "path.module",
+								Range: Range{
+									Start: Pos{Line: 3, Column: 31},
+									End:   Pos{Line: 3, Column: 42},
+								},
+							},
+						},
+					},
+				},
+				Dependencies: []*Dependency{
+					{
+						From: "local.name",
+						To:   "local.prefix",
+						Range: Range{
+							Start: Pos{Line: 3, Column: 15},
+							End:   Pos{Line: 3, Column: 27},
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: []string{
+				`# lets EC2 assume the role
+data "aws_iam_policy_document" "assume" {
+  statement {
+    actions = ["sts:AssumeRole"]
+  }
+}
+`,
+			},
+			ModuleName: "test",
+			Module: &TFModule{
+				Title: "test",
+				DataSources: []*DataSource{
+					{
+						Type:        "aws_iam_policy_document",
+						Name:        "assume",
+						Description: "lets EC2 assume the role",
+						Range: Range{
+							Start: Pos{Line: 2, Column: 1},
+							End:   Pos{Line: 6, Column: 1},
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: []string{
+				`variable "broken" {`,
+			},
+			ModuleName: "test",
+			Err:        true,
+		},
+		{
+			Input:      []string{},
+			ModuleName: "",
+			Err:        true,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("ParseHCL2 %v", i), func(t *testing.T) {
+			result, err := ParseHCL2(c.Input, c.ModuleName)
+			if c.Err {
+				assert.Error(t, err, "Expected an error")
+			} else {
+				assert.NoError(t, err, "Expected no error")
+				assert.Equal(t, c.Module, result, "")
+			}
+		})
+	}
+}
+
+func TestFindAndParseHCL2(t *testing.T) {
+	for _, dir := range []string{"./testdata/modules/depth1", "./testdata/modules/depth2"} {
+		t.Run(fmt.Sprintf("FindAndParseHCL2 %v", dir), func(t *testing.T) {
+			hcl1, err := FindAndParse(dir)
+			assert.NoError(t, err, "")
+			hcl2, err := FindAndParseWithOptions(dir, Options{Parser: ParseHCL2Files})
+			assert.NoError(t, err, "")
+			assert.Equal(t, hcl1, hcl2.Modules, "HCL1 and HCL2 results should match")
+		})
+	}
+}
+
+func TestCtyString(t *testing.T) {
+	cases := []struct {
+		Input  cty.Value
+		Result string
+		OK     bool
+	}{
+		{
+			Input:  cty.StringVal("hello"),
+			Result: "hello",
+			OK:     true,
+		},
+		{
+			Input:  cty.NumberIntVal(3),
+			Result: "3",
+			OK:     true,
+		},
+		{
+			Input:  cty.True,
+			Result: "true",
+			OK:     true,
+		},
+		{
+			Input:  cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.NumberIntVal(1)}),
+			Result: "[a, 1]",
+			OK:     true,
+		},
+		{
+			Input:  cty.NullVal(cty.String),
+			Result: "null",
+			OK:     true,
+		},
+		{
+			Input: cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("b")}),
+			OK:    false,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("ctyString %v", i), func(t *testing.T) {
+			result, ok := ctyString(c.Input)
+			assert.Equal(t, c.OK, ok, "")
+			assert.Equal(t, c.Result, result, "")
+		})
+	}
+}
+
+func TestExtractHCL2Comments(t *testing.T) {
+	src := `// first
+// second
+
+/* block */
+resource "a" "b" { # trailing
+}
+`
+	tokens, diags := hclsyntax.LexConfig([]byte(src), "", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diags.HasErrors(), "")
+
+	comments, lead := extractHCL2Comments(tokens)
+	assert.Equal(t, []*Comment{
+		{Text: "first second", Col: 1, Line: 1},
+		{Text: "block", Col: 1, Line: 4},
+	}, comments, "")
+	assert.Equal(t, comments[0], lead[2], "")
+	assert.Equal(t, comments[1], lead[4], "")
+}
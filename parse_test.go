@@ -1,7 +1,12 @@
 package tf_docs
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/hashicorp/hcl/hcl/token"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +22,7 @@ func TestFindAndParse(t *testing.T) {
 			InputDir: "./testdata/modules/depth1",
 			Result: []*TFModule{
 				{
+					Dir:   "./testdata/modules/depth1",
 					Title: "depth1",
 					Link: "depth1",
 					Variables: []*Variable{
@@ -26,12 +32,22 @@ func TestFindAndParse(t *testing.T) {
 							Description: "this is a variable",
 							Default:     "",
 							Required:    true,
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 3, Column: 1},
+								End:      Pos{Line: 6, Column: 1},
+							},
 						},
 					},
 					Outputs: []*Output{
 						{
 							Description: "output description",
 							Name:        "test",
+							Range: Range{
+								Filename: "file.tf",
+								Start:    Pos{Line: 1, Column: 1},
+								End:      Pos{Line: 4, Column: 1},
+							},
 						},
 					},
 					Resources: []*Resource{
@@ -39,6 +55,11 @@ func TestFindAndParse(t *testing.T) {
 							Type:        "aws_ami",
 							Name:        "ami",
 							Description: "this is a resource",
+							Range: Range{
+								Filename: "file.tf",
+								Start:    Pos{Line: 7, Column: 1},
+								End:      Pos{Line: 9, Column: 1},
+							},
 						},
 					},
 					Modules:     []*Module{
@@ -46,6 +67,11 @@ func TestFindAndParse(t *testing.T) {
 							Name:        "test",
 							Description: "here's a module",
 							Source:      "../",
+							Range: Range{
+								Filename: "file.tf",
+								Start:    Pos{Line: 12, Column: 1},
+								End:      Pos{Line: 14, Column: 1},
+							},
 						},
 					},
 					Description: "depth1 is a test module",
@@ -56,6 +82,7 @@ func TestFindAndParse(t *testing.T) {
 			InputDir: "./testdata/modules/depth2",
 			Result: []*TFModule{
 				{
+					Dir:   "./testdata/modules/depth2/module1",
 					Title: "module1",
 					Link: "module1",
 					Variables: []*Variable{
@@ -65,12 +92,22 @@ func TestFindAndParse(t *testing.T) {
 							Description: "this is a variable",
 							Default:     "",
 							Required:    true,
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 3, Column: 1},
+								End:      Pos{Line: 6, Column: 1},
+							},
 						},
 					},
 					Outputs: []*Output{
 						{
 							Description: "output description",
 							Name:        "test",
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 8, Column: 1},
+								End:      Pos{Line: 11, Column: 1},
+							},
 						},
 					},
 					Resources: []*Resource{
@@ -78,6 +115,11 @@ func TestFindAndParse(t *testing.T) {
 							Type:        "aws_ami",
 							Name:        "ami",
 							Description: "this is a resource",
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 14, Column: 1},
+								End:      Pos{Line: 16, Column: 1},
+							},
 						},
 					},
 					Modules:     []*Module{
@@ -85,11 +127,17 @@ func TestFindAndParse(t *testing.T) {
 							Name:        "test",
 							Description: "here's a module",
 							Source:      "../",
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 19, Column: 1},
+								End:      Pos{Line: 21, Column: 1},
+							},
 						},
 					},
 					Description: "module1 is a test module",
 				},
 				{
+					Dir:   "./testdata/modules/depth2/module2",
 					Title: "module2",
 					Link: "module2",
 					Variables: []*Variable{
@@ -99,12 +147,22 @@ func TestFindAndParse(t *testing.T) {
 							Description: "this is a variable",
 							Default:     "",
 							Required:    true,
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 3, Column: 1},
+								End:      Pos{Line: 6, Column: 1},
+							},
 						},
 					},
 					Outputs: []*Output{
 						{
 							Description: "output description",
 							Name:        "test",
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 8, Column: 1},
+								End:      Pos{Line: 11, Column: 1},
+							},
 						},
 					},
 					Resources: []*Resource{
@@ -112,6 +170,11 @@ func TestFindAndParse(t *testing.T) {
 							Type:        "aws_ami",
 							Name:        "ami",
 							Description: "this is a resource",
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 14, Column: 1},
+								End:      Pos{Line: 16, Column: 1},
+							},
 						},
 					},
 					Modules:     []*Module{
@@ -119,6 +182,11 @@ func TestFindAndParse(t *testing.T) {
 							Name:        "test",
 							Description: "here's a module",
 							Source:      "../",
+							Range: Range{
+								Filename: "main.tf",
+								Start:    Pos{Line: 19, Column: 1},
+								End:      Pos{Line: 21, Column: 1},
+							},
 						},
 					},
 					Description: "module2 is a test module",
@@ -230,6 +298,10 @@ output "val2" {
 						Description: "desc",
 						Default:     "",
 						Required:    true,
+						Range: Range{
+							Start: Pos{Line: 2, Column: 1},
+							End:   Pos{Line: 5, Column: 1},
+						},
 					},
 					{
 						Name:        "test2",
@@ -237,16 +309,28 @@ output "val2" {
 						Description: "desc",
 						Default:     "",
 						Required:    true,
+						Range: Range{
+							Start: Pos{Line: 1, Column: 1},
+							End:   Pos{Line: 4, Column: 1},
+						},
 					},
 				},
 				Outputs: []*Output{
 					{
 						Description: "output desc",
 						Name:        "val",
+						Range: Range{
+							Start: Pos{Line: 17, Column: 1},
+							End:   Pos{Line: 20, Column: 1},
+						},
 					},
 					{
 						Description: "output desc",
 						Name:        "val2",
+						Range: Range{
+							Start: Pos{Line: 16, Column: 1},
+							End:   Pos{Line: 19, Column: 1},
+						},
 					},
 				},
 				Resources: []*Resource{
@@ -254,11 +338,19 @@ output "val2" {
 						Type:        "aws",
 						Name:        "test",
 						Description: "resource desc",
+						Range: Range{
+							Start: Pos{Line: 13, Column: 1},
+							End:   Pos{Line: 15, Column: 1},
+						},
 					},
 					{
 						Type:        "aws",
 						Name:        "test2",
 						Description: "resource desc",
+						Range: Range{
+							Start: Pos{Line: 12, Column: 1},
+							End:   Pos{Line: 14, Column: 1},
+						},
 					},
 				},
 				Modules: []*Module{
@@ -266,11 +358,19 @@ output "val2" {
 						Name:        "test",
 						Description: "module that does a thing",
 						Source:      "../../test",
+						Range: Range{
+							Start: Pos{Line: 8, Column: 1},
+							End:   Pos{Line: 10, Column: 1},
+						},
 					},
 					{
 						Name:        "test2",
 						Description: "module that does a thing",
 						Source:      "../../test",
+						Range: Range{
+							Start: Pos{Line: 7, Column: 1},
+							End:   Pos{Line: 9, Column: 1},
+						},
 					},
 				},
 				Description: "test",
@@ -309,13 +409,35 @@ func TestTraverseDirectories(t *testing.T) {
 
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("traverseDirectories %v", i), func(t *testing.T) {
-			result, err := traverseDirectory(c.DirPath)
+			result, err := traverseDirectory(c.DirPath, nil)
 			assert.NoError(t, err)
 			assert.EqualValues(t, c.ModulePaths, result, "Should be equal")
 		})
 	}
 }
 
+func TestParseModuleDir(t *testing.T) {
+	dirs, err := FindModuleDirs("./testdata/modules/depth2", Options{})
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{"./testdata/modules/depth2/module1", "./testdata/modules/depth2/module2"}, dirs, "")
+
+	result, err := FindAndParseWithOptions("./testdata/modules/depth2", Options{Parser: ParseHCL2Files})
+	assert.NoError(t, err, "")
+	for i, d := range dirs {
+		module, err := ParseModuleDir("./testdata/modules/depth2", d, Options{Parser: ParseHCL2Files})
+		assert.NoError(t, err, "")
+		assert.Equal(t, result.Modules[i], module, "")
+	}
+
+	module, err := ParseModuleDir("./testdata/modules", "./testdata/modules/depth2/module1", Options{})
+	assert.NoError(t, err, "")
+	assert.Equal(t, "depth2", module.Path, "")
+	assert.Equal(t, "depth2_module1", module.Link, "")
+
+	_, err = ParseModuleDir("./testdata/modules", "./testdata/modules/missing", Options{})
+	assert.Error(t, err, "")
+}
+
 func TestExtractDescription(t *testing.T) {
 	cases := []struct {
 		Comments   []*Comment
@@ -395,6 +517,69 @@ func TestExtractModules(t *testing.T) {
 			},
 			Err: false,
 		},
+		{
+			Input: []*Value{
+				{
+					Key: map[string][]string{
+						"module": {"vpc"},
+					},
+					Val: map[string]string{
+						"source":  "terraform-aws-modules/vpc/aws",
+						"version": "2.0.0",
+					},
+					Raw: map[string]string{
+						"source":     "\"terraform-aws-modules/vpc/aws\"",
+						"version":    "\"2.0.0\"",
+						"count":      "var.create ? 1 : 0",
+						"depends_on": "[aws_iam_role.vpc]",
+						"providers":  "{ aws = aws.west }",
+						"cidr":       "var.cidr",
+						"name":       "\"main\"",
+					},
+					Lists: map[string][]string{
+						"depends_on": {"aws_iam_role.vpc"},
+					},
+					Blocks: []*Value{
+						{
+							Key: map[string][]string{
+								"providers": {},
+							},
+							Val: map[string]string{
+								"aws": "aws.west",
+							},
+						},
+					},
+				},
+			},
+			Result: []*Module{
+				{
+					Name:    "vpc",
+					Source:  "terraform-aws-modules/vpc/aws",
+					Version: "2.0.0",
+					Providers: map[string]string{
+						"aws": "aws.west",
+					},
+					Count:     "var.create ? 1 : 0",
+					DependsOn: []string{"aws_iam_role.vpc"},
+					Inputs: map[string]string{
+						"cidr": "var.cidr",
+						"name": "\"main\"",
+					},
+				},
+			},
+			Err: false,
+		},
+		{
+			Input: []*Value{
+				{
+					Key: map[string][]string{
+						"module": {"name"},
+					},
+					Val: map[string]string{},
+				},
+			},
+			Err: true,
+		},
 	}
 
 	for i, c := range cases {
@@ -454,6 +639,65 @@ func TestExtractResources(t *testing.T) {
 	}
 }
 
+func TestExtractDataSources(t *testing.T) {
+	cases := []struct {
+		Input  []*Value
+		Result []*DataSource
+		Err    bool
+	}{
+		{
+			Input: []*Value{
+				{
+					Key: map[string][]string{
+						"data": {"aws_iam_policy_document", "assume"},
+					},
+					Val: map[string]string{},
+					Comment: Comment{
+						Text: "Describe me a data source",
+					},
+				},
+				{
+					Key: map[string][]string{
+						"resource": {"type", "name"},
+					},
+					Val: map[string]string{},
+				},
+			},
+			Result: []*DataSource{
+				{
+					Type:        "aws_iam_policy_document",
+					Name:        "assume",
+					Description: "Describe me a data source",
+				},
+			},
+			Err: false,
+		},
+		{
+			Input: []*Value{
+				{
+					Key: map[string][]string{
+						"data": {"aws_ami"},
+					},
+					Val: map[string]string{},
+				},
+			},
+			Err: true,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("extractDataSources %v", i), func(t *testing.T) {
+			result, err := extractDataSources(c.Input)
+			if c.Err {
+				assert.Error(t, err, "Expected an error")
+			} else {
+				assert.NoError(t, err, "expected no error")
+				assert.Equal(t, c.Result, result, "expected equality")
+			}
+		})
+	}
+}
+
 func TestExtractOutputs(t *testing.T) {
 	cases := []struct {
 		Input  []*Value
@@ -541,7 +785,19 @@ func TestExtractVariables(t *testing.T) {
 						"description": "a variable",
 						"default":     "yes",
 					},
-					Comment: Comment{},
+					HasDefault: true,
+					Comment:    Comment{},
+				},
+				{
+					Key: map[string][]string{
+						"variable": {"empty"},
+					},
+					Val: map[string]string{
+						"type":    "string",
+						"default": "",
+					},
+					HasDefault: true,
+					Comment:    Comment{},
 				},
 				{
 					Key: map[string][]string{
@@ -569,6 +825,12 @@ func TestExtractVariables(t *testing.T) {
 					Default:     "yes",
 					Required:    false,
 				},
+				{
+					Name:     "empty",
+					Type:     "string",
+					Default:  "",
+					Required: false,
+				},
 			},
 			Err: false,
 		},
@@ -602,6 +864,34 @@ func TestExtractVariables(t *testing.T) {
 	}
 }
 
+func TestParseEmptyDefault(t *testing.T) {
+	cases := []struct {
+		Parse ParseFunc
+		Src   string
+	}{
+		{
+			Parse: ParseFiles,
+			Src:   "variable \"x\" {\n  type = \"string\"\n}\n\nvariable \"y\" {\n  type    = \"string\"\n  default = \"\"\n}\n\nvariable \"z\" {\n  type    = \"map\"\n  default = {}\n}\n",
+		},
+		{
+			Parse: ParseHCL2Files,
+			Src:   "variable \"x\" {}\n\nvariable \"y\" {\n  default = \"\"\n}\n\nvariable \"z\" {\n  default = {}\n}\n",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("parseEmptyDefault %v", i), func(t *testing.T) {
+			module, err := c.Parse([]*File{{Name: "main.tf", Body: c.Src}}, "defaults")
+			assert.NoError(t, err, "")
+			required := map[string]bool{}
+			for _, variable := range module.Variables {
+				required[variable.Name] = variable.Required
+			}
+			assert.Equal(t, map[string]bool{"x": true, "y": false, "z": false}, required, "")
+		})
+	}
+}
+
 func TestExtractElements(t *testing.T) {
 	cases := []struct {
 		Type   string
@@ -726,6 +1016,16 @@ func TestExtractValues(t *testing.T) {
 					Val: map[string]string{
 						"value": "testVal",
 					},
+					Raw: map[string]string{
+						"value": "testVal",
+					},
+					Attributes: map[string]*Attribute{
+						"value": {
+							Range: Range{
+								End: Pos{Column: 7},
+							},
+						},
+					},
 					Comment: Comment{},
 				},
 			},
@@ -807,6 +1107,16 @@ func TestExtractValues(t *testing.T) {
 					Val: map[string]string{
 						"value": "testVal",
 					},
+					Raw: map[string]string{
+						"value": "testVal",
+					},
+					Attributes: map[string]*Attribute{
+						"value": {
+							Range: Range{
+								End: Pos{Column: 7},
+							},
+						},
+					},
 					Comment: Comment{},
 				},
 			},
@@ -975,6 +1285,120 @@ func TestParseValues(t *testing.T) {
 	}
 }
 
+func TestParseModuleCalls(t *testing.T) {
+	cases := []struct {
+		Parse  ParseFunc
+		Input  string
+		Module *Module
+	}{
+		{
+			Parse: ParseFiles,
+			Input: `module "vpc" {
+  source     = "terraform-aws-modules/vpc/aws"
+  version    = "1.0.0"
+  cidr       = "${var.cidr}"
+  zones      = ["a", "b"]
+  count      = "${var.create}"
+  depends_on = ["aws_iam_role.vpc"]
+
+  providers = {
+    "aws" = "aws.west"
+  }
+}
+`,
+			Module: &Module{
+				Name:    "vpc",
+				Source:  "terraform-aws-modules/vpc/aws",
+				Version: "1.0.0",
+				Providers: map[string]string{
+					"aws": "aws.west",
+				},
+				Count:     "\"${var.create}\"",
+				DependsOn: []string{"aws_iam_role.vpc"},
+				Inputs: map[string]string{
+					"cidr":  "\"${var.cidr}\"",
+					"zones": "[\"a\", \"b\"]",
+				},
+			},
+		},
+		{
+			Parse: ParseHCL2Files,
+			Input: `module "vpc" {
+  source     = "terraform-aws-modules/vpc/aws"
+  version    = "~> 2.0"
+  for_each   = toset(var.names)
+  name       = each.key
+  tags       = merge(var.tags, { Name = each.key })
+  depends_on = [aws_iam_role.vpc, module.base]
+
+  providers = {
+    aws = aws.west
+  }
+}
+`,
+			Module: &Module{
+				Name:    "vpc",
+				Source:  "terraform-aws-modules/vpc/aws",
+				Version: "~> 2.0",
+				Providers: map[string]string{
+					"aws": "aws.west",
+				},
+				ForEach:   "toset(var.names)",
+				DependsOn: []string{"aws_iam_role.vpc", "module.base"},
+				Inputs: map[string]string{
+					"name": "each.key",
+					"tags": "merge(var.tags, { Name = each.key })",
+				},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("moduleCalls %v", i), func(t *testing.T) {
+			result, err := c.Parse([]*File{{Body: c.Input}}, "test")
+			assert.NoError(t, err, "Expected no error")
+			assert.Len(t, result.Modules, 1, "")
+			result.Modules[0].Range = Range{}
+			result.Modules[0].References = nil
+			assert.Equal(t, c.Module, result.Modules[0], "")
+		})
+	}
+}
+
+func TestRawNode(t *testing.T) {
+	cases := []struct {
+		Input  ast.Node
+		Result string
+	}{
+		{
+			Input:  &ast.LiteralType{Token: token.Token{Text: "\"hello\""}},
+			Result: "\"hello\"",
+		},
+		{
+			Input: &ast.ListType{List: []ast.Node{
+				&ast.LiteralType{Token: token.Token{Text: "\"a\""}},
+				&ast.LiteralType{Token: token.Token{Text: "1"}},
+			}},
+			Result: "[\"a\", 1]",
+		},
+		{
+			Input: &ast.ObjectType{List: &ast.ObjectList{Items: []*ast.ObjectItem{
+				{
+					Keys: []*ast.ObjectKey{{Token: token.Token{Text: "key"}}},
+					Val:  &ast.LiteralType{Token: token.Token{Text: "\"value\""}},
+				},
+			}}},
+			Result: "{key = \"value\"}",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("rawNode %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Result, rawNode(c.Input), "should be equal")
+		})
+	}
+}
+
 func TestExtractComments(t *testing.T) {
 	cases := []struct {
 		Input  []*ast.CommentGroup
@@ -1045,6 +1469,10 @@ func TestTidyComment(t *testing.T) {
 			Input:  "//    Leading space",
 			Result: "Leading space",
 		},
+		{
+			Input:  "# Hash comment",
+			Result: "Hash comment",
+		},
 		{
 			Input:  "/* Multiline on one */",
 			Result: "Multiline on one",
@@ -1163,3 +1591,113 @@ func TestTrimStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestFindAndParseContinueOnError(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"good/main.tf":   "variable \"a\" {\n  type = \"string\"\n}\n",
+		"broken/main.tf": "variable \"a\" {\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "")
+		assert.NoError(t, ioutil.WriteFile(path, []byte(body), 0644), "")
+	}
+
+	result, err := FindAndParseWithOptions(root, Options{ContinueOnError: true})
+	assert.NoError(t, err, "")
+	assert.Len(t, result.Modules, 1, "")
+	assert.Equal(t, "good", result.Modules[0].Title, "")
+	assert.Len(t, result.Failures, 1, "")
+	assert.Equal(t, root+"/broken", result.Failures[0].Dir, "")
+	var syntaxErr *SyntaxError
+	assert.True(t, errors.As(result.Failures[0].Err, &syntaxErr), "")
+
+	result, err = FindAndParseWithOptions(root, Options{})
+	assert.Error(t, err, "")
+	assert.Len(t, result.Modules, 1, "")
+	assert.Len(t, result.Failures, 1, "")
+}
+
+func TestFindAndParseConcurrency(t *testing.T) {
+	root := t.TempDir()
+	var expected []string
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("module%02d", i)
+		expected = append(expected, name)
+		path := filepath.Join(root, name, "main.tf")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "")
+		assert.NoError(t, ioutil.WriteFile(path, []byte("output \"o\" {\n  value = \"v\"\n}\n"), 0644), "")
+	}
+
+	for _, concurrency := range []int{0, 1, 4, 100} {
+		t.Run(fmt.Sprintf("Concurrency %v", concurrency), func(t *testing.T) {
+			result, err := FindAndParseWithOptions(root, Options{Concurrency: concurrency})
+			assert.NoError(t, err, "")
+			var titles []string
+			for _, module := range result.Modules {
+				titles = append(titles, module.Title)
+			}
+			assert.Equal(t, expected, titles, "modules should be returned in directory order")
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := FindAndParseContext(ctx, root, Options{Concurrency: 2})
+	assert.Equal(t, context.Canceled, err, "")
+}
+
+func TestExtractLocals(t *testing.T) {
+	module, err := Parse([]string{`locals {
+  name = "${var.prefix}-app"
+  zones = ["a", "b"]
+}
+
+locals {
+  id = "${aws_vpc.main.id}"
+}
+`}, "test")
+	assert.NoError(t, err, "")
+	assert.Equal(t, []*Local{
+		{
+			Name: "name",
+			Range: Range{
+				Start: Pos{Line: 2, Column: 3},
+				End:   Pos{Line: 2, Column: 29},
+			},
+			References: []*Reference{
+				{
+					Name: "var.prefix",
+					Range: Range{
+						Start: Pos{Line: 2, Column: 10},
+						End:   Pos{Line: 2, Column: 29},
+					},
+				},
+			},
+		},
+		{
+			Name: "zones",
+			Range: Range{
+				Start: Pos{Line: 3, Column: 3},
+				End:   Pos{Line: 3, Column: 21},
+			},
+		},
+		{
+			Name: "id",
+			Range: Range{
+				Start: Pos{Line: 7, Column: 3},
+				End:   Pos{Line: 7, Column: 28},
+			},
+			References: []*Reference{
+				{
+					Name: "aws_vpc.main.id",
+					Range: Range{
+						Start: Pos{Line: 7, Column: 8},
+						End:   Pos{Line: 7, Column: 28},
+					},
+				},
+			},
+		},
+	}, module.Locals, "")
+}
@@ -0,0 +1,113 @@
+package tf_docs
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	PROVIDER  = "provider"
+	TERRAFORM = "terraform"
+)
+
+// Provider is a provider a module needs, merged from its provider blocks and the required_providers
+// of its terraform blocks.
+type Provider struct {
+	Name    string
+	Alias   string
+	Source  string
+	Version string
+	Range   Range
+	// References are the references made by the provider's configuration blocks.
+	References []*Reference
+}
+
+// extractProviders returns the providers configured by provider blocks and required by terraform
+// blocks across every file of a module, along with the module's required Terraform version.
+// Aliased providers inherit the source and version required for their provider.
+func extractProviders(values []*Value) ([]*Provider, string, error) {
+	var providers []*Provider
+	var requiredVersions []string
+	var errs Errors
+	byName := map[string]*Provider{}
+
+	provider := func(name, alias string, r Range) *Provider {
+		key := name + "." + alias
+		p, ok := byName[key]
+		if !ok {
+			p = &Provider{Name: name, Alias: alias, Range: r}
+			byName[key] = p
+			providers = append(providers, p)
+		}
+		return p
+	}
+
+	for _, t := range extractElement(values, TERRAFORM) {
+		if version, ok := t.Val["required_version"]; ok {
+			requiredVersions = append(requiredVersions, version)
+		}
+		for _, required := range t.Blocks {
+			if _, ok := required.Key["required_providers"]; !ok {
+				continue
+			}
+			entries := map[string]bool{}
+			for _, entry := range required.Blocks {
+				for name := range entry.Key {
+					entries[name] = true
+					p := provider(name, "", entry.Range)
+					p.Source = entry.Val["source"]
+					p.Version = joinConstraints(p.Version, entry.Val["version"])
+				}
+			}
+			// The pre 0.13 form maps the name of a provider directly to its version.
+			for name, version := range required.Val {
+				if !entries[name] {
+					p := provider(name, "", required.Range)
+					p.Version = joinConstraints(p.Version, version)
+				}
+			}
+		}
+	}
+
+	for _, p := range extractElement(values, PROVIDER) {
+		if len(p.Key[PROVIDER]) != 1 {
+			errs.add(newSchemaError(p, PROVIDER, "name is required for a provider"))
+			continue
+		}
+		configured := provider(p.Key[PROVIDER][0], p.Val["alias"], p.Range)
+		configured.Range = p.Range
+		configured.References = append(configured.References, p.References...)
+		configured.Version = joinConstraints(configured.Version, p.Val["version"])
+	}
+
+	for _, p := range providers {
+		if required, ok := byName[p.Name+"."]; ok && p.Alias != "" {
+			if p.Source == "" {
+				p.Source = required.Source
+			}
+			if p.Version == "" {
+				p.Version = required.Version
+			}
+		}
+	}
+
+	sort.SliceStable(providers, func(i, j int) bool {
+		if providers[i].Name != providers[j].Name {
+			return providers[i].Name < providers[j].Name
+		}
+		return providers[i].Alias < providers[j].Alias
+	})
+
+	return providers, strings.Join(requiredVersions, ", "), errs.err()
+}
+
+// joinConstraints combines two version constraints, either of which may be empty.
+func joinConstraints(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case b == "":
+		return a
+	}
+	return a + ", " + b
+}
@@ -0,0 +1,156 @@
+package tf_docs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProviders(t *testing.T) {
+	cases := []struct {
+		Parse           ParseFunc
+		Files           []*File
+		Providers       []*Provider
+		RequiredVersion string
+	}{
+		{
+			Parse: ParseHCL2Files,
+			Files: []*File{
+				{
+					Name: "versions.tf",
+					Body: `terraform {
+  required_version = ">= 0.13"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+    random = "~> 2.0"
+  }
+}
+`,
+				},
+				{
+					Name: "providers.tf",
+					Body: `provider "aws" {
+  region = "eu-west-1"
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+}
+
+provider "null" {
+  version = "~> 2.1"
+}
+`,
+				},
+			},
+			Providers: []*Provider{
+				{
+					Name:    "aws",
+					Source:  "hashicorp/aws",
+					Version: "~> 3.0",
+					Range: Range{
+						Filename: "providers.tf",
+						Start:    Pos{Line: 1, Column: 1},
+						End:      Pos{Line: 3, Column: 1},
+					},
+				},
+				{
+					Name:    "aws",
+					Alias:   "west",
+					Source:  "hashicorp/aws",
+					Version: "~> 3.0",
+					Range: Range{
+						Filename: "providers.tf",
+						Start:    Pos{Line: 5, Column: 1},
+						End:      Pos{Line: 8, Column: 1},
+					},
+				},
+				{
+					Name:    "null",
+					Version: "~> 2.1",
+					Range: Range{
+						Filename: "providers.tf",
+						Start:    Pos{Line: 10, Column: 1},
+						End:      Pos{Line: 12, Column: 1},
+					},
+				},
+				{
+					Name:    "random",
+					Version: "~> 2.0",
+					Range: Range{
+						Filename: "versions.tf",
+						Start:    Pos{Line: 4, Column: 3},
+						End:      Pos{Line: 10, Column: 3},
+					},
+				},
+			},
+			RequiredVersion: ">= 0.13",
+		},
+		{
+			Parse: ParseFiles,
+			Files: []*File{
+				{
+					Name: "main.tf",
+					Body: `terraform {
+  required_version = ">= 0.11"
+
+  required_providers {
+    aws = "~> 2.0"
+  }
+}
+
+provider "aws" {
+  version = ">= 2.7"
+}
+`,
+				},
+			},
+			Providers: []*Provider{
+				{
+					Name:    "aws",
+					Version: "~> 2.0, >= 2.7",
+					Range: Range{
+						Filename: "main.tf",
+						Start:    Pos{Line: 9, Column: 1},
+						End:      Pos{Line: 11, Column: 1},
+					},
+				},
+			},
+			RequiredVersion: ">= 0.11",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Providers %v", i), func(t *testing.T) {
+			module, err := c.Parse(c.Files, "test")
+			assert.NoError(t, err, "Expected no error")
+			assert.Equal(t, c.Providers, module.Providers, "")
+			assert.Equal(t, c.RequiredVersion, module.RequiredVersion, "")
+		})
+	}
+}
+
+func TestJoinConstraints(t *testing.T) {
+	cases := []struct {
+		A      string
+		B      string
+		Result string
+	}{
+		{A: "", B: "~> 1.0", Result: "~> 1.0"},
+		{A: "~> 1.0", B: "", Result: "~> 1.0"},
+		{A: "~> 1.0", B: "~> 1.0", Result: "~> 1.0"},
+		{A: ">= 1.0", B: "< 2.0", Result: ">= 1.0, < 2.0"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("joinConstraints %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Result, joinConstraints(c.A, c.B), "")
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package tf_docs
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Reference is a reference, made by an expression, to another object such as var.name or
+// aws_vpc.main.id. Name is the reference's attribute names joined with dots, leaving out any index,
+// so aws_instance.web[0].id is named aws_instance.web.id.
+type Reference struct {
+	Name  string
+	Range Range
+}
+
+// Dependency is a reference from one object of a module to another, both named by their address
+// within the module: aws_vpc.main, data.aws_ami.ubuntu, module.vpc, var.name, local.name or
+// output.name. Range is where the first such reference was made.
+type Dependency struct {
+	From  string
+	To    string
+	Range Range
+}
+
+// interpolation matches the ${...} interpolations within an HCL1 string.
+var interpolation = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// interpolationReference matches a reference within an HCL1 interpolation, such as var.name or
+// aws_instance.web.*.id.
+var interpolationReference = regexp.MustCompile(`[A-Za-z_][\w-]*(\.[\w*-]+)+`)
+
+// parseReferences returns the references made by every interpolation within an HCL1 object,
+// including those within nested lists and objects.
+func parseReferences(node ast.Node) []*Reference {
+	var references []*Reference
+
+	switch n := node.(type) {
+	case *ast.LiteralType:
+		for _, match := range interpolation.FindAllStringSubmatch(n.Token.Text, -1) {
+			for _, ref := range interpolationReference.FindAllString(match[1], -1) {
+				var names []string
+				for _, part := range strings.Split(ref, ".") {
+					if part == "*" || isIndex(part) {
+						continue
+					}
+					names = append(names, part)
+				}
+				references = append(references, &Reference{
+					Name: strings.Join(names, "."),
+					Range: Range{
+						Start: Pos{Line: n.Token.Pos.Line, Column: n.Token.Pos.Column},
+						End:   Pos{Line: n.Token.Pos.Line, Column: n.Token.Pos.Column + len(n.Token.Text)},
+					},
+				})
+			}
+		}
+	case *ast.ListType:
+		for _, v := range n.List {
+			references = append(references, parseReferences(v)...)
+		}
+	case *ast.ObjectType:
+		for _, item := range n.List.Items {
+			references = append(references, parseReferences(item.Val)...)
+		}
+	}
+
+	return references
+}
+
+// isIndex reports whether part of an HCL1 reference is a numeric index, as in aws_instance.web.0.id.
+func isIndex(part string) bool {
+	for _, r := range part {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return part != ""
+}
+
+// hcl2References returns the references made by the expressions within an HCL2 body, including
+// those within nested blocks.
+func hcl2References(body *hclsyntax.Body) []*Reference {
+	var references []*Reference
+
+	for _, attr := range sortedAttributes(body) {
+		for _, traversal := range attr.Expr.Variables() {
+			references = append(references, traversalReference(traversal))
+		}
+	}
+	for _, block := range body.Blocks {
+		references = append(references, hcl2References(block.Body)...)
+	}
+
+	return references
+}
+
+// traversalReference returns the Reference made by an HCL2 traversal.
+func traversalReference(traversal hcl.Traversal) *Reference {
+	var names []string
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, s.Name)
+		case hcl.TraverseAttr:
+			names = append(names, s.Name)
+		}
+	}
+
+	r := traversal.SourceRange()
+	return &Reference{
+		Name: strings.Join(names, "."),
+		Range: Range{
+			Filename: r.Filename,
+			Start:    Pos{Line: r.Start.Line, Column: r.Start.Column},
+			End:      Pos{Line: r.End.Line, Column: r.End.Column},
+		},
+	}
+}
+
+// moduleReferences returns every reference made within a module.
+func moduleReferences(module *TFModule) []*Reference {
+	var references []*Reference
+
+	for _, resource := range module.Resources {
+		references = append(references, resource.References...)
+	}
+	for _, dataSource := range module.DataSources {
+		references = append(references, dataSource.References...)
+	}
+	for _, output := range module.Outputs {
+		references = append(references, output.References...)
+	}
+	for _, call := range module.Modules {
+		references = append(references, call.References...)
+	}
+	for _, local := range module.Locals {
+		references = append(references, local.References...)
+	}
+	for _, provider := range module.Providers {
+		references = append(references, provider.References...)
+	}
+
+	return references
+}
+
+// extractDependencies returns the references made by the resources, data sources, locals, module
+// calls and outputs of a module to the objects it declares. References to anything else, such as
+// path.module or an undeclared variable, are left out.
+func extractDependencies(module *TFModule) []*Dependency {
+	var dependencies []*Dependency
+
+	declared := map[string]bool{}
+	for _, address := range ModuleAddresses(module) {
+		declared[address] = true
+	}
+
+	add := func(from string, references []*Reference) {
+		seen := map[string]bool{}
+		for _, reference := range references {
+			to := referenceAddress(reference.Name)
+			if !declared[to] || to == from || seen[to] {
+				continue
+			}
+			seen[to] = true
+			dependencies = append(dependencies, &Dependency{From: from, To: to, Range: reference.Range})
+		}
+	}
+	for _, resource := range module.Resources {
+		add(resource.Type+"."+resource.Name, resource.References)
+	}
+	for _, dataSource := range module.DataSources {
+		add(DATA+"."+dataSource.Type+"."+dataSource.Name, dataSource.References)
+	}
+	for _, local := range module.Locals {
+		add("local."+local.Name, local.References)
+	}
+	for _, call := range module.Modules {
+		add(MODULE+"."+call.Name, call.References)
+	}
+	for _, output := range module.Outputs {
+		add(OUTPUT+"."+output.Name, output.References)
+	}
+
+	return dependencies
+}
+
+// trackUsage records on each variable of a module the objects that reference it, and on each output
+// the objects it exposes.
+func trackUsage(module *TFModule) {
+	variables := map[string]*Variable{}
+	for _, variable := range module.Variables {
+		variables["var."+variable.Name] = variable
+	}
+	outputs := map[string]*Output{}
+	for _, output := range module.Outputs {
+		outputs[OUTPUT+"."+output.Name] = output
+	}
+
+	for _, dependency := range module.Dependencies {
+		if variable, ok := variables[dependency.To]; ok {
+			variable.UsedBy = append(variable.UsedBy, dependency.From)
+		}
+		if output, ok := outputs[dependency.From]; ok {
+			output.Exposes = append(output.Exposes, dependency.To)
+		}
+	}
+
+	// Providers are not objects that can be referenced, so they have no Dependencies of their own.
+	for _, provider := range module.Providers {
+		address := PROVIDER + "." + provider.Name
+		if provider.Alias != "" {
+			address += "." + provider.Alias
+		}
+		seen := map[string]bool{}
+		for _, reference := range provider.References {
+			to := referenceAddress(reference.Name)
+			if variable, ok := variables[to]; ok && !seen[to] {
+				seen[to] = true
+				variable.UsedBy = append(variable.UsedBy, address)
+			}
+		}
+	}
+}
+
+// ModuleAddresses returns the address of every object declared by a module, the names used by
+// Dependency: variables, locals, resources, data sources, module calls and then outputs.
+func ModuleAddresses(module *TFModule) []string {
+	var addresses []string
+
+	for _, variable := range module.Variables {
+		addresses = append(addresses, "var."+variable.Name)
+	}
+	for _, local := range module.Locals {
+		addresses = append(addresses, "local."+local.Name)
+	}
+	for _, resource := range module.Resources {
+		addresses = append(addresses, resource.Type+"."+resource.Name)
+	}
+	for _, dataSource := range module.DataSources {
+		addresses = append(addresses, DATA+"."+dataSource.Type+"."+dataSource.Name)
+	}
+	for _, call := range module.Modules {
+		addresses = append(addresses, MODULE+"."+call.Name)
+	}
+	for _, output := range module.Outputs {
+		addresses = append(addresses, OUTPUT+"."+output.Name)
+	}
+
+	return addresses
+}
+
+// referenceAddress returns the address of the object a reference refers to, such as var.name for
+// var.name.key or aws_vpc.main for aws_vpc.main.id.
+func referenceAddress(name string) string {
+	parts := strings.Split(name, ".")
+
+	length := 2
+	switch parts[0] {
+	case "var", "local", MODULE:
+	case DATA:
+		length = 3
+	case "path", "terraform", "count", "each", "self":
+		return ""
+	}
+	if len(parts) < length {
+		return ""
+	}
+
+	return strings.Join(parts[:length], ".")
+}
@@ -0,0 +1,75 @@
+package tf_docs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceAddress(t *testing.T) {
+	cases := []struct {
+		Input  string
+		Result string
+	}{
+		{Input: "var.name", Result: "var.name"},
+		{Input: "var.tags.Name", Result: "var.tags"},
+		{Input: "local.prefix", Result: "local.prefix"},
+		{Input: "aws_vpc.main.id", Result: "aws_vpc.main"},
+		{Input: "data.aws_ami.ubuntu.id", Result: "data.aws_ami.ubuntu"},
+		{Input: "module.vpc.id", Result: "module.vpc"},
+		{Input: "path.module", Result: ""},
+		{Input: "each.value", Result: ""},
+		{Input: "data.aws_ami", Result: ""},
+		{Input: "aws_vpc", Result: ""},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("referenceAddress %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Result, referenceAddress(c.Input), "")
+		})
+	}
+}
+
+func TestExtractDependencies(t *testing.T) {
+	module := &TFModule{
+		Variables: []*Variable{{Name: "name"}},
+		Locals: []*Local{
+			{Name: "prefix", References: []*Reference{{Name: "var.name"}, {Name: "var.missing"}}},
+		},
+		Resources: []*Resource{
+			{Type: "aws_vpc", Name: "main", References: []*Reference{{Name: "local.prefix"}, {Name: "local.prefix"}, {Name: "path.module"}}},
+		},
+		DataSources: []*DataSource{
+			{Type: "aws_ami", Name: "ubuntu", References: []*Reference{{Name: "var.name"}}},
+		},
+		Modules: []*Module{
+			{Name: "app", References: []*Reference{{Name: "aws_vpc.main.id"}, {Name: "data.aws_ami.ubuntu.id"}}},
+		},
+		Outputs: []*Output{
+			{Name: "id", References: []*Reference{{Name: "module.app.id"}}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"var.name",
+		"local.prefix",
+		"aws_vpc.main",
+		"data.aws_ami.ubuntu",
+		"module.app",
+		"output.id",
+	}, ModuleAddresses(module), "")
+
+	var dependencies []string
+	for _, dependency := range extractDependencies(module) {
+		dependencies = append(dependencies, dependency.From+" -> "+dependency.To)
+	}
+	assert.Equal(t, []string{
+		"aws_vpc.main -> local.prefix",
+		"data.aws_ami.ubuntu -> var.name",
+		"local.prefix -> var.name",
+		"module.app -> aws_vpc.main",
+		"module.app -> data.aws_ami.ubuntu",
+		"output.id -> module.app",
+	}, dependencies, "")
+}
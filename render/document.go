@@ -0,0 +1,39 @@
+package render
+
+import (
+	"io"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/document"
+)
+
+// Document renders modules in the versioned format of the document package, encoded as JSON, YAML
+// or TOML.
+type Document struct {
+	write func(w io.Writer, modules []*tf_docs.TFModule) error
+}
+
+// NewJSON returns a Renderer that writes JSON documents.
+func NewJSON() *Document {
+	return &Document{write: document.WriteJSON}
+}
+
+// NewYAML returns a Renderer that writes YAML documents.
+func NewYAML() *Document {
+	return &Document{write: document.WriteYAML}
+}
+
+// NewTOML returns a Renderer that writes TOML documents.
+func NewTOML() *Document {
+	return &Document{write: document.WriteTOML}
+}
+
+// RenderModule writes a document containing a single module.
+func (d *Document) RenderModule(w io.Writer, module *tf_docs.TFModule) error {
+	return d.write(w, []*tf_docs.TFModule{module})
+}
+
+// RenderModules writes a document containing every module.
+func (d *Document) RenderModules(w io.Writer, modules []*tf_docs.TFModule) error {
+	return d.write(w, modules)
+}
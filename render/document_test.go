@@ -0,0 +1,44 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/document"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument(t *testing.T) {
+	modules := []*tf_docs.TFModule{
+		{Title: "vpc", Link: "vpc"},
+		{Title: "app", Link: "app"},
+	}
+
+	cases := []struct {
+		Renderer *Document
+		Read     func(io.Reader) ([]*tf_docs.TFModule, error)
+	}{
+		{Renderer: NewJSON(), Read: document.ReadJSON},
+		{Renderer: NewYAML(), Read: document.ReadYAML},
+		{Renderer: NewTOML(), Read: document.ReadTOML},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Document %v", i), func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, c.Renderer.RenderModule(&buf, modules[1]), "")
+			loaded, err := c.Read(&buf)
+			assert.NoError(t, err, "")
+			assert.Equal(t, modules[1:], loaded, "")
+
+			buf.Reset()
+			assert.NoError(t, c.Renderer.RenderModules(&buf, modules), "")
+			loaded, err = c.Read(&buf)
+			assert.NoError(t, err, "")
+			assert.Equal(t, modules, loaded, "")
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// The markers Inject looks for unless given others.
+const (
+	DefaultBeginMarker = "<!-- BEGIN_TF_DOCS -->"
+	DefaultEndMarker   = "<!-- END_TF_DOCS -->"
+)
+
+var (
+	// ErrMissingMarkers is returned by Inject when a document contains neither marker.
+	ErrMissingMarkers = errors.New("markers not found")
+	// ErrUnbalancedMarkers is returned by Inject when a document does not contain exactly one begin
+	// marker followed by one end marker.
+	ErrUnbalancedMarkers = errors.New("markers are unbalanced")
+)
+
+// Markers delimit the generated region of a document.
+type Markers struct {
+	Begin string
+	End   string
+}
+
+// DefaultMarkers returns the markers Inject uses by default.
+func DefaultMarkers() Markers {
+	return Markers{Begin: DefaultBeginMarker, End: DefaultEndMarker}
+}
+
+// Inject returns document with the text between its begin and end markers replaced by generated,
+// leaving the markers and everything around them untouched. Injecting the same text again returns
+// the document unchanged. Empty markers are replaced by the default markers.
+func Inject(document, generated []byte, markers Markers) ([]byte, error) {
+	if markers.Begin == "" {
+		markers.Begin = DefaultBeginMarker
+	}
+	if markers.End == "" {
+		markers.End = DefaultEndMarker
+	}
+	begin, end := []byte(markers.Begin), []byte(markers.End)
+
+	begins, ends := bytes.Count(document, begin), bytes.Count(document, end)
+	switch {
+	case begins == 0 && ends == 0:
+		return nil, fmt.Errorf("%w: expected %s and %s", ErrMissingMarkers, markers.Begin, markers.End)
+	case begins != 1 || ends != 1:
+		return nil, fmt.Errorf("%w: found %d %s and %d %s, expected one of each", ErrUnbalancedMarkers, begins, markers.Begin, ends, markers.End)
+	}
+	start := bytes.Index(document, begin) + len(begin)
+	stop := bytes.Index(document, end)
+	if stop < start {
+		return nil, fmt.Errorf("%w: %s comes before %s", ErrUnbalancedMarkers, markers.End, markers.Begin)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(document[:start])
+	buf.WriteString("\n")
+	buf.Write(generated)
+	if len(generated) > 0 && !bytes.HasSuffix(generated, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	buf.Write(document[stop:])
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInject(t *testing.T) {
+	cases := []struct {
+		Document  string
+		Generated string
+		Markers   Markers
+		Result    string
+		Err       error
+	}{
+		{
+			Document:  "# vpc\n\nprose\n\n<!-- BEGIN_TF_DOCS -->\nold\n<!-- END_TF_DOCS -->\n\nmore prose\n",
+			Generated: "new\n",
+			Result:    "# vpc\n\nprose\n\n<!-- BEGIN_TF_DOCS -->\nnew\n<!-- END_TF_DOCS -->\n\nmore prose\n",
+		},
+		{
+			Document:  "<!-- BEGIN_TF_DOCS --><!-- END_TF_DOCS -->",
+			Generated: "new",
+			Result:    "<!-- BEGIN_TF_DOCS -->\nnew\n<!-- END_TF_DOCS -->",
+		},
+		{
+			Document:  "[//]: # (begin)\nold\n[//]: # (end)\n",
+			Generated: "new\n",
+			Markers:   Markers{Begin: "[//]: # (begin)", End: "[//]: # (end)"},
+			Result:    "[//]: # (begin)\nnew\n[//]: # (end)\n",
+		},
+		{
+			Document:  "no markers\n",
+			Generated: "new\n",
+			Err:       ErrMissingMarkers,
+		},
+		{
+			Document:  "<!-- BEGIN_TF_DOCS -->\nold\n",
+			Generated: "new\n",
+			Err:       ErrUnbalancedMarkers,
+		},
+		{
+			Document:  "<!-- BEGIN_TF_DOCS -->\n<!-- END_TF_DOCS -->\n<!-- BEGIN_TF_DOCS -->\n<!-- END_TF_DOCS -->\n",
+			Generated: "new\n",
+			Err:       ErrUnbalancedMarkers,
+		},
+		{
+			Document:  "<!-- END_TF_DOCS -->\n<!-- BEGIN_TF_DOCS -->\n",
+			Generated: "new\n",
+			Err:       ErrUnbalancedMarkers,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Inject %v", i), func(t *testing.T) {
+			result, err := Inject([]byte(c.Document), []byte(c.Generated), c.Markers)
+			if c.Err != nil {
+				assert.ErrorIs(t, err, c.Err, "")
+				return
+			}
+			assert.NoError(t, err, "")
+			assert.Equal(t, c.Result, string(result), "")
+
+			again, err := Inject(result, []byte(c.Generated), c.Markers)
+			assert.NoError(t, err, "")
+			assert.Equal(t, c.Result, string(again), "injecting twice should not change the document")
+		})
+	}
+}
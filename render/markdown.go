@@ -0,0 +1,54 @@
+package render
+
+import (
+	"embed"
+	"sort"
+	"strings"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Markdown renders modules as Markdown, with the module's requirements followed by a table for each of
+// its variables, outputs, resources, data sources and modules. Every module is preceded by an anchor
+// built from TFModule.Link. It executes the built-in "markdown" template.
+type Markdown struct {
+	*Template
+}
+
+// NewMarkdown returns a Renderer that writes Markdown.
+func NewMarkdown() *Markdown {
+	return &Markdown{Template: builtinTemplate("markdown")}
+}
+
+// escapeMarkdown makes text safe to use within a Markdown table cell.
+func escapeMarkdown(text string) string {
+	text = strings.Replace(text, "|", "\\|", -1)
+	text = strings.Replace(text, "\r\n", "<br>", -1)
+	text = strings.Replace(text, "\n", "<br>", -1)
+	return text
+}
+
+// codeMarkdown formats text as inline code within a Markdown table cell. Empty text is left empty.
+func codeMarkdown(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + escapeMarkdown(text) + "`"
+}
+
+// inputsMarkdown formats the inputs passed to a module as one "name = expression" line per input,
+// sorted by name, within a Markdown table cell.
+func inputsMarkdown(inputs map[string]string) string {
+	var names []string
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, codeMarkdown(name+" = "+inputs[name]))
+	}
+	return strings.Join(lines, "<br>")
+}
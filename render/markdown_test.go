@@ -0,0 +1,182 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownRenderModule(t *testing.T) {
+	cases := []struct {
+		Module *tf_docs.TFModule
+		Result string
+	}{
+		{
+			Module: &tf_docs.TFModule{
+				Title:       "vpc",
+				Link:        "network-vpc",
+				Description: "vpc creates a VPC",
+				Providers: []*tf_docs.Provider{
+					{
+						Name:    "aws",
+						Source:  "hashicorp/aws",
+						Version: "~> 3.0",
+					},
+				},
+				RequiredVersion: ">= 0.13",
+				Variables: []*tf_docs.Variable{
+					{
+						Name:        "cidr",
+						Type:        "string",
+						Description: "the CIDR | range",
+						Required:    true,
+					},
+					{
+						Name:    "zones",
+						Type:    "list",
+						Default: "[a, b]",
+					},
+				},
+				Outputs: []*tf_docs.Output{
+					{
+						Name:        "id",
+						Description: "the VPC id",
+					},
+				},
+				Resources: []*tf_docs.Resource{
+					{
+						Type:        "aws_vpc",
+						Name:        "main",
+						Description: "the VPC",
+					},
+				},
+				DataSources: []*tf_docs.DataSource{
+					{
+						Type:        "aws_iam_policy_document",
+						Name:        "assume",
+						Description: "lets EC2 assume the role",
+					},
+				},
+				Modules: []*tf_docs.Module{
+					{
+						Name:        "subnets",
+						Source:      "../subnets",
+						Description: "subnets for the VPC",
+						Inputs: map[string]string{
+							"vpc_id": "aws_vpc.main.id",
+							"cidr":   "var.cidr",
+						},
+					},
+				},
+			},
+			Result: "<a name=\"network-vpc\"></a>\n" +
+				"# vpc\n" +
+				"\n" +
+				"vpc creates a VPC\n" +
+				"\n" +
+				"## Requirements\n" +
+				"\n" +
+				"Terraform `>= 0.13`\n" +
+				"\n" +
+				"| Provider | Alias | Source | Version |\n" +
+				"|----------|-------|--------|---------|\n" +
+				"| aws |  | `hashicorp/aws` | `~> 3.0` |\n" +
+				"\n" +
+				"## Variables\n" +
+				"\n" +
+				"| Name | Type | Default | Required | Description |\n" +
+				"|------|------|---------|----------|-------------|\n" +
+				"| cidr | `string` |  | yes | the CIDR \\| range |\n" +
+				"| zones | `list` | `[a, b]` | no |  |\n" +
+				"\n" +
+				"## Outputs\n" +
+				"\n" +
+				"| Name | Description |\n" +
+				"|------|-------------|\n" +
+				"| id | the VPC id |\n" +
+				"\n" +
+				"## Resources\n" +
+				"\n" +
+				"| Type | Name | Description |\n" +
+				"|------|------|-------------|\n" +
+				"| aws_vpc | main | the VPC |\n" +
+				"\n" +
+				"## Data Sources\n" +
+				"\n" +
+				"| Type | Name | Description |\n" +
+				"|------|------|-------------|\n" +
+				"| aws_iam_policy_document | assume | lets EC2 assume the role |\n" +
+				"\n" +
+				"## Modules\n" +
+				"\n" +
+				"| Name | Source | Version | Inputs | Description |\n" +
+				"|------|--------|---------|--------|-------------|\n" +
+				"| subnets | `../subnets` |  | `cidr = var.cidr`<br>`vpc_id = aws_vpc.main.id` | subnets for the VPC |\n",
+		},
+		{
+			Module: &tf_docs.TFModule{
+				Title: "empty",
+				Link:  "empty",
+			},
+			Result: "<a name=\"empty\"></a>\n# empty\n",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("RenderModule %v", i), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewMarkdown().RenderModule(&buf, c.Module)
+			assert.NoError(t, err, "Expected no error")
+			assert.Equal(t, c.Result, buf.String(), "")
+		})
+	}
+}
+
+func TestMarkdownRenderModules(t *testing.T) {
+	modules := []*tf_docs.TFModule{
+		{Title: "one", Link: "one"},
+		{Title: "two", Link: "path-two", Description: "two does things"},
+	}
+	expected := "# Modules\n" +
+		"\n" +
+		"- [one](#one)\n" +
+		"- [two](#path-two)\n" +
+		"\n" +
+		"<a name=\"one\"></a>\n" +
+		"## one\n" +
+		"\n" +
+		"<a name=\"path-two\"></a>\n" +
+		"## two\n" +
+		"\n" +
+		"two does things\n"
+
+	var buf bytes.Buffer
+	err := NewMarkdown().RenderModules(&buf, modules)
+	assert.NoError(t, err, "Expected no error")
+	assert.Equal(t, expected, buf.String(), "")
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	cases := []struct {
+		Input  string
+		Result string
+	}{
+		{
+			Input:  "a | b",
+			Result: "a \\| b",
+		},
+		{
+			Input:  "line one\nline two",
+			Result: "line one<br>line two",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("escapeMarkdown %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Result, escapeMarkdown(c.Input), "should be equal")
+		})
+	}
+}
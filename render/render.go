@@ -0,0 +1,56 @@
+// Package render turns the modules found by tf_docs into documentation.
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// Renderer writes documentation for parsed Terraform modules.
+type Renderer interface {
+	// RenderModule writes the documentation for a single module.
+	RenderModule(w io.Writer, module *tf_docs.TFModule) error
+	// RenderModules writes the documentation for a set of modules, such as the result of
+	// FindAndParse, as a single document.
+	RenderModules(w io.Writer, modules []*tf_docs.TFModule) error
+}
+
+// format describes an output format that can be selected by name.
+type format struct {
+	renderer  func() Renderer
+	extension string
+}
+
+// formats maps the name of each output format to its Renderer.
+var formats = map[string]format{
+	"markdown": {renderer: func() Renderer { return NewMarkdown() }, extension: ".md"},
+	"md":       {renderer: func() Renderer { return NewMarkdown() }, extension: ".md"},
+	"json":     {renderer: func() Renderer { return NewJSON() }, extension: ".json"},
+	"yaml":     {renderer: func() Renderer { return NewYAML() }, extension: ".yaml"},
+	"yml":      {renderer: func() Renderer { return NewYAML() }, extension: ".yaml"},
+	"toml":     {renderer: func() Renderer { return NewTOML() }, extension: ".toml"},
+}
+
+// ForFormat returns the Renderer for the named output format along with the file extension, including
+// the leading dot, used for files written in that format.
+func ForFormat(name string) (Renderer, string, error) {
+	f, ok := formats[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown format %q, expected one of %s", name, strings.Join(Formats(), ", "))
+	}
+	return f.renderer(), f.extension, nil
+}
+
+// Formats returns the names of the supported output formats.
+func Formats() []string {
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package render
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForFormat(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Extension string
+		Err       bool
+	}{
+		{
+			Name:      "markdown",
+			Extension: ".md",
+		},
+		{
+			Name:      "md",
+			Extension: ".md",
+		},
+		{
+			Name:      "json",
+			Extension: ".json",
+		},
+		{
+			Name:      "yml",
+			Extension: ".yaml",
+		},
+		{
+			Name:      "toml",
+			Extension: ".toml",
+		},
+		{
+			Name: "docx",
+			Err:  true,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("ForFormat %v", i), func(t *testing.T) {
+			renderer, ext, err := ForFormat(c.Name)
+			if c.Err {
+				assert.Error(t, err, "Expected an error")
+			} else {
+				assert.NoError(t, err, "Expected no error")
+				assert.NotNil(t, renderer, "")
+				assert.Equal(t, c.Extension, ext, "")
+			}
+		})
+	}
+}
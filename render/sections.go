@@ -0,0 +1,111 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// sections remove each part of a module's documentation that can be left out, by name. A module's
+// title, path and link are always kept.
+var sections = map[string]func(module *tf_docs.TFModule){
+	"description": func(module *tf_docs.TFModule) {
+		module.Description = ""
+	},
+	"requirements": func(module *tf_docs.TFModule) {
+		module.RequiredVersion = ""
+		module.Providers = nil
+	},
+	"variables": func(module *tf_docs.TFModule) {
+		module.Variables = nil
+	},
+	"outputs": func(module *tf_docs.TFModule) {
+		module.Outputs = nil
+	},
+	"resources": func(module *tf_docs.TFModule) {
+		module.Resources = nil
+	},
+	"data_sources": func(module *tf_docs.TFModule) {
+		module.DataSources = nil
+	},
+	"modules": func(module *tf_docs.TFModule) {
+		module.Modules = nil
+	},
+	"locals": func(module *tf_docs.TFModule) {
+		module.Locals = nil
+	},
+	"dependencies": func(module *tf_docs.TFModule) {
+		module.Dependencies = nil
+	},
+}
+
+// Sections returns the names of the sections of a module's documentation that OnlySections can
+// choose between, sorted.
+func Sections() []string {
+	var names []string
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OnlySections returns a function that returns a copy of a module without every section of its
+// documentation but those named, so that they are not rendered. The module itself is left as parsed.
+// It returns an error if a name is not one of Sections.
+func OnlySections(names []string) (func(module *tf_docs.TFModule) *tf_docs.TFModule, error) {
+	keep := map[string]bool{}
+	for _, name := range names {
+		if _, ok := sections[name]; !ok {
+			return nil, fmt.Errorf("unknown section %q, expected one of %s", name, strings.Join(Sections(), ", "))
+		}
+		keep[name] = true
+	}
+
+	return func(module *tf_docs.TFModule) *tf_docs.TFModule {
+		only := *module
+		for name, remove := range sections {
+			if !keep[name] {
+				remove(&only)
+			}
+		}
+		return &only
+	}, nil
+}
+
+// WithSections returns a Renderer that renders only the named sections of each module's
+// documentation through renderer. Without any names, every section is rendered and renderer is
+// returned as is.
+func WithSections(renderer Renderer, names []string) (Renderer, error) {
+	if len(names) == 0 {
+		return renderer, nil
+	}
+	only, err := OnlySections(names)
+	if err != nil {
+		return nil, err
+	}
+	return &sectionsRenderer{renderer: renderer, only: only}, nil
+}
+
+// sectionsRenderer renders copies of modules keeping only some of their sections.
+type sectionsRenderer struct {
+	renderer Renderer
+	only     func(module *tf_docs.TFModule) *tf_docs.TFModule
+}
+
+// RenderModule renders a copy of module keeping only the chosen sections.
+func (r *sectionsRenderer) RenderModule(w io.Writer, module *tf_docs.TFModule) error {
+	return r.renderer.RenderModule(w, r.only(module))
+}
+
+// RenderModules renders copies of modules keeping only the chosen sections.
+func (r *sectionsRenderer) RenderModules(w io.Writer, modules []*tf_docs.TFModule) error {
+	only := make([]*tf_docs.TFModule, len(modules))
+	for i, module := range modules {
+		only[i] = r.only(module)
+	}
+	return r.renderer.RenderModules(w, only)
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnlySections(t *testing.T) {
+	module := &tf_docs.TFModule{
+		Title:           "vpc",
+		Link:            "vpc",
+		Description:     "vpc creates a network",
+		RequiredVersion: ">= 0.12",
+		Providers:       []*tf_docs.Provider{{Name: "aws"}},
+		Variables:       []*tf_docs.Variable{{Name: "cidr"}},
+		Outputs:         []*tf_docs.Output{{Name: "id"}},
+		Resources:       []*tf_docs.Resource{{Type: "aws_vpc", Name: "main"}},
+	}
+
+	only, err := OnlySections([]string{"description", "variables"})
+	assert.NoError(t, err, "")
+	assert.Equal(t, &tf_docs.TFModule{
+		Title:       "vpc",
+		Link:        "vpc",
+		Description: "vpc creates a network",
+		Variables:   []*tf_docs.Variable{{Name: "cidr"}},
+	}, only(module), "")
+	assert.Equal(t, []*tf_docs.Resource{{Type: "aws_vpc", Name: "main"}}, module.Resources, "")
+
+	_, err = OnlySections([]string{"variables", "inputs"})
+	assert.EqualError(t, err, `unknown section "inputs", expected one of data_sources, dependencies, description, locals, modules, outputs, requirements, resources, variables`, "")
+}
+
+func TestWithSections(t *testing.T) {
+	module := &tf_docs.TFModule{
+		Title:       "vpc",
+		Link:        "vpc",
+		Description: "vpc creates a network",
+		Variables:   []*tf_docs.Variable{{Name: "cidr", Required: true}},
+		Outputs:     []*tf_docs.Output{{Name: "id"}},
+	}
+
+	renderer, err := WithSections(NewMarkdown(), []string{"outputs"})
+	assert.NoError(t, err, "")
+	var buf bytes.Buffer
+	assert.NoError(t, renderer.RenderModules(&buf, []*tf_docs.TFModule{module}), "")
+	assert.Contains(t, buf.String(), "id", "")
+	assert.NotContains(t, buf.String(), "cidr", "")
+	assert.NotContains(t, buf.String(), "vpc creates a network", "")
+	assert.Equal(t, "vpc creates a network", module.Description, "")
+	assert.Len(t, module.Variables, 1, "")
+
+	markdown := NewMarkdown()
+	renderer, err = WithSections(markdown, nil)
+	assert.NoError(t, err, "")
+	assert.Equal(t, markdown, renderer, "")
+
+	_, err = WithSections(markdown, []string{"inputs"})
+	assert.Error(t, err, "")
+}
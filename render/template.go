@@ -0,0 +1,207 @@
+package render
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// Template renders modules using Go templates, either text/template or html/template. The templates
+// must define two named templates:
+//
+//   - "module" renders a single module. It is executed with a *Section.
+//   - "modules" renders a set of modules, such as the result of FindAndParse, as a single document.
+//     It is executed with the []*tf_docs.TFModule.
+//
+// Besides the functions built into Go templates, templates can use:
+//
+//	heading LEVEL       a Markdown heading of LEVEL, such as "###"
+//	inc N               N + 1, for the heading level of a subsection
+//	section MODULE N    a *Section for MODULE at heading level N, to execute "module" with
+//	anchor LINK         an anchor name built from a TFModule.Link, safe to use in a URL fragment
+//	escape TEXT         TEXT made safe to use within a Markdown table cell
+//	code TEXT           TEXT as inline code within a Markdown table cell, or "" if TEXT is empty
+//	yesno BOOL          "yes" or "no"
+//	inputs MAP          the inputs of a module call, one "name = expression" per line of a table cell
+//	sortBy FIELD SLICE  a copy of SLICE sorted by one of its elements' string fields, such as "Name"
+//	required VARIABLES  the variables that are required
+//	optional VARIABLES  the variables that have a default
+type Template struct {
+	tmpl interface {
+		ExecuteTemplate(w io.Writer, name string, data interface{}) error
+	}
+}
+
+// Section is the data the "module" template is executed with: a module and the heading level its
+// title is written at.
+type Section struct {
+	Module *tf_docs.TFModule
+	Level  int
+}
+
+// requiredTemplates are the templates every Template must define.
+var requiredTemplates = []string{"module", "modules"}
+
+// NewTemplate returns a Template parsing the text/template files at paths.
+func NewTemplate(paths ...string) (*Template, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no template files given")
+	}
+	tmpl, err := template.New(filepath.Base(paths[0])).Funcs(Funcs()).ParseFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range requiredTemplates {
+		if tmpl.Lookup(name) == nil {
+			return nil, fmt.Errorf("template %q is not defined by %s", name, strings.Join(paths, ", "))
+		}
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// NewHTMLTemplate returns a Template parsing the html/template files at paths. Text from modules is
+// escaped for the context it is written in.
+func NewHTMLTemplate(paths ...string) (*Template, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no template files given")
+	}
+	tmpl, err := htmltemplate.New(filepath.Base(paths[0])).Funcs(htmltemplate.FuncMap(Funcs())).ParseFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range requiredTemplates {
+		if tmpl.Lookup(name) == nil {
+			return nil, fmt.Errorf("template %q is not defined by %s", name, strings.Join(paths, ", "))
+		}
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// builtinTemplate returns a Template parsing the built-in text/template named name.
+func builtinTemplate(name string) *Template {
+	file := name + ".tmpl"
+	tmpl := template.Must(template.New(file).Funcs(Funcs()).ParseFS(templates, "templates/"+file))
+	return &Template{tmpl: tmpl}
+}
+
+// BuiltinTemplate returns the source of the built-in template named name, such as "markdown", as a
+// starting point for a template of your own.
+func BuiltinTemplate(name string) (string, error) {
+	source, err := templates.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		return "", fmt.Errorf("unknown built-in template %q", name)
+	}
+	return string(source), nil
+}
+
+// RenderModule executes the "module" template for a single module at heading level 1.
+func (t *Template) RenderModule(w io.Writer, module *tf_docs.TFModule) error {
+	return t.tmpl.ExecuteTemplate(w, "module", &Section{Module: module, Level: 1})
+}
+
+// RenderModules executes the "modules" template for a set of modules.
+func (t *Template) RenderModules(w io.Writer, modules []*tf_docs.TFModule) error {
+	return t.tmpl.ExecuteTemplate(w, "modules", modules)
+}
+
+// Funcs returns the helper functions available to templates, described by Template.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"heading": func(level int) string { return strings.Repeat("#", level) },
+		"inc":     func(i int) int { return i + 1 },
+		"section": func(module *tf_docs.TFModule, level int) *Section {
+			return &Section{Module: module, Level: level}
+		},
+		"anchor": anchor,
+		"escape": escapeMarkdown,
+		"code":   codeMarkdown,
+		"yesno": func(b bool) string {
+			if b {
+				return "yes"
+			}
+			return "no"
+		},
+		"inputs":   inputsMarkdown,
+		"sortBy":   sortBy,
+		"required": func(variables []*tf_docs.Variable) []*tf_docs.Variable { return filterVariables(variables, true) },
+		"optional": func(variables []*tf_docs.Variable) []*tf_docs.Variable { return filterVariables(variables, false) },
+	}
+}
+
+// anchorUnsafe matches the characters anchor replaces.
+var anchorUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// anchor returns an anchor name for a TFModule.Link, replacing anything other than letters, digits,
+// underscores and hyphens with a hyphen.
+func anchor(link string) string {
+	return anchorUnsafe.ReplaceAllString(link, "-")
+}
+
+// sortBy returns a copy of slice sorted by the named string field of its elements, which may be
+// structs or pointers to structs. The sort is stable.
+func sortBy(field string, slice interface{}) (interface{}, error) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("sortBy: expected a slice, got %T", slice)
+	}
+
+	sorted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(sorted, v)
+
+	key := func(i int) (string, error) {
+		elem := reflect.Indirect(sorted.Index(i))
+		if elem.Kind() != reflect.Struct {
+			return "", fmt.Errorf("sortBy: expected a slice of structs, got %T", slice)
+		}
+		f := elem.FieldByName(field)
+		if !f.IsValid() || f.Kind() != reflect.String {
+			return "", fmt.Errorf("sortBy: %s has no string field %q", elem.Type(), field)
+		}
+		return f.String(), nil
+	}
+	keys := make([]string, sorted.Len())
+	for i := range keys {
+		k, err := key(i)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = k
+	}
+
+	swap := reflect.Swapper(sorted.Interface())
+	sort.Stable(&keyedSlice{keys: keys, swap: swap})
+
+	return sorted.Interface(), nil
+}
+
+// keyedSlice sorts a slice by a key per element, swapping the keys along with the elements.
+type keyedSlice struct {
+	keys []string
+	swap func(i, j int)
+}
+
+func (s *keyedSlice) Len() int           { return len(s.keys) }
+func (s *keyedSlice) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s *keyedSlice) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.swap(i, j)
+}
+
+// filterVariables returns the variables that are required, or those that are not.
+func filterVariables(variables []*tf_docs.Variable, required bool) []*tf_docs.Variable {
+	var result []*tf_docs.Variable
+	for _, variable := range variables {
+		if variable.Required == required {
+			result = append(result, variable)
+		}
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTemplate(t *testing.T, source string) string {
+	path := filepath.Join(t.TempDir(), "docs.tmpl")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(source), 0644), "")
+	return path
+}
+
+func TestNewTemplate(t *testing.T) {
+	module := &tf_docs.TFModule{
+		Title: "vpc",
+		Link:  "network_vpc",
+		Variables: []*tf_docs.Variable{
+			{Name: "zones", Default: "[a]"},
+			{Name: "name", Required: true},
+			{Name: "cidr", Required: true},
+		},
+		Description: "vpc creates <b>networks</b>",
+	}
+	source := `{{define "modules"}}{{range .}}[{{.Title}}](#{{anchor .Link}}) {{end}}{{end}}
+{{- define "module"}}{{.Module.Description}}: {{range sortBy "Name" (required .Module.Variables)}}{{.Name}} {{end}}/ {{range optional .Module.Variables}}{{.Name}}{{end}}{{end}}`
+
+	cases := []struct {
+		New     func(paths ...string) (*Template, error)
+		Module  string
+		Modules string
+	}{
+		{
+			New:     NewTemplate,
+			Module:  "vpc creates <b>networks</b>: cidr name / zones",
+			Modules: "[vpc](#network_vpc) ",
+		},
+		{
+			New:     NewHTMLTemplate,
+			Module:  "vpc creates &lt;b&gt;networks&lt;/b&gt;: cidr name / zones",
+			Modules: "[vpc](#network_vpc) ",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("NewTemplate %v", i), func(t *testing.T) {
+			tmpl, err := c.New(writeTemplate(t, source))
+			assert.NoError(t, err, "")
+
+			var buf bytes.Buffer
+			assert.NoError(t, tmpl.RenderModule(&buf, module), "")
+			assert.Equal(t, c.Module, buf.String(), "")
+
+			buf.Reset()
+			assert.NoError(t, tmpl.RenderModules(&buf, []*tf_docs.TFModule{module}), "")
+			assert.Equal(t, c.Modules, buf.String(), "")
+
+			_, err = c.New(writeTemplate(t, `{{define "module"}}{{end}}`))
+			assert.Error(t, err, "a template without modules should fail")
+			_, err = c.New(writeTemplate(t, `{{define "module"}}{{nope}}{{end}}`))
+			assert.Error(t, err, "an unknown function should fail")
+			_, err = c.New()
+			assert.Error(t, err, "no templates should fail")
+		})
+	}
+}
+
+func TestBuiltinTemplate(t *testing.T) {
+	source, err := BuiltinTemplate("markdown")
+	assert.NoError(t, err, "")
+	tmpl, err := NewTemplate(writeTemplate(t, source))
+	assert.NoError(t, err, "")
+
+	module := &tf_docs.TFModule{Title: "vpc", Link: "vpc", Description: "vpc creates a VPC"}
+	var custom, builtin bytes.Buffer
+	assert.NoError(t, tmpl.RenderModule(&custom, module), "")
+	assert.NoError(t, NewMarkdown().RenderModule(&builtin, module), "")
+	assert.Equal(t, builtin.String(), custom.String(), "")
+
+	_, err = BuiltinTemplate("docx")
+	assert.Error(t, err, "")
+}
+
+func TestAnchor(t *testing.T) {
+	assert.Equal(t, "network-vpc_main", anchor("network-vpc_main"), "")
+	assert.Equal(t, "my-module-v2", anchor("my module.v2"), "")
+}
+
+func TestSortBy(t *testing.T) {
+	outputs := []*tf_docs.Output{{Name: "b"}, {Name: "c"}, {Name: "a"}}
+	sorted, err := sortBy("Name", outputs)
+	assert.NoError(t, err, "")
+	assert.Equal(t, []*tf_docs.Output{{Name: "a"}, {Name: "b"}, {Name: "c"}}, sorted, "")
+	assert.Equal(t, "b", outputs[0].Name, "the slice given should not be sorted")
+
+	resources := []tf_docs.Resource{{Type: "b"}, {Type: "a"}}
+	sorted, err = sortBy("Type", resources)
+	assert.NoError(t, err, "")
+	assert.Equal(t, []tf_docs.Resource{{Type: "a"}, {Type: "b"}}, sorted, "")
+
+	_, err = sortBy("Missing", outputs)
+	assert.Error(t, err, "")
+	_, err = sortBy("References", outputs)
+	assert.Error(t, err, "")
+	_, err = sortBy("Name", "not a slice")
+	assert.Error(t, err, "")
+}
@@ -0,0 +1,227 @@
+// Package serve serves the HTML documentation site of a directory of modules for previewing. Pages
+// are rendered when they are requested, and open pages reload themselves when the modules change.
+package serve
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/render"
+	"github.com/nathmclean/tf_docs/site"
+	"github.com/nathmclean/tf_docs/watch"
+)
+
+// EventsPath is the path of the stream of server-sent events telling pages to reload.
+const EventsPath = "/_tf_docs/events"
+
+// reloadScript is added to every page to reload it when the server sends an event.
+const reloadScript = `<script>new EventSource("` + EventsPath + `").onmessage = function () { window.location.reload(); };</script>
+`
+
+// failuresTemplate lists the modules that failed to parse at the top of every page.
+var failuresTemplate = template.Must(template.New("failures").Parse(`<div class="failures" style="margin-bottom: 1em; padding: 0.5em 1em; color: #86181d; background: #ffeef0; border: 1px solid #f97583;">
+<p>These modules could not be parsed and show their last version, if any:</p>
+{{- range .}}
+<pre>{{.Err}}</pre>
+{{- end}}
+</div>
+`))
+
+// Server serves the documentation site of a set of modules.
+type Server struct {
+	// only returns the copy of a module that is documented, keeping the chosen sections, if set.
+	only func(module *tf_docs.TFModule) *tf_docs.TFModule
+
+	// mu guards modules and site.
+	mu      sync.RWMutex
+	modules *watch.Modules
+	site    *site.Site
+
+	clientsMu sync.Mutex
+	clients   map[chan struct{}]bool
+}
+
+// New returns a Server of modules, such as those loaded by watch.Load, documenting only the named
+// sections of each module as described by render.OnlySections, or every section without any names.
+func New(modules *watch.Modules, sections []string) (*Server, error) {
+	server := &Server{modules: modules, clients: map[chan struct{}]bool{}}
+	if len(sections) > 0 {
+		only, err := render.OnlySections(sections)
+		if err != nil {
+			return nil, err
+		}
+		server.only = only
+	}
+
+	s, err := server.newSite()
+	if err != nil {
+		return nil, err
+	}
+	server.site = s
+	return server, nil
+}
+
+// newSite returns the site of the current modules.
+func (s *Server) newSite() (*site.Site, error) {
+	modules := s.modules.Modules()
+	if s.only != nil {
+		only := make([]*tf_docs.TFModule, len(modules))
+		for i, module := range modules {
+			only[i] = s.only(module)
+		}
+		modules = only
+	}
+	return site.New(modules)
+}
+
+// Update re-parses the modules in the changed directories, as reported by a watch.Watcher, and tells
+// open pages to reload if any module was parsed again or removed. It returns the directories of those
+// modules.
+func (s *Server) Update(changed []string) ([]string, error) {
+	s.mu.Lock()
+	updated, err := s.modules.Update(changed)
+	if err == nil && len(updated) > 0 {
+		var updatedSite *site.Site
+		if updatedSite, err = s.newSite(); err == nil {
+			s.site = updatedSite
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(updated) > 0 {
+		s.reload()
+	}
+	return updated, nil
+}
+
+// Failures returns the modules that failed to parse the last time they were parsed.
+func (s *Server) Failures() []*tf_docs.ModuleFailure {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.modules.Failures()
+}
+
+// ServeHTTP serves the pages of the site, and the events telling them to reload at EventsPath.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == EventsPath {
+		s.serveEvents(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	if name == "" {
+		name = site.Index
+	}
+
+	var buf bytes.Buffer
+	s.mu.RLock()
+	err := s.site.WritePage(&buf, name)
+	failures := s.modules.Failures()
+	s.mu.RUnlock()
+	if errors.Is(err, os.ErrNotExist) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	body := buf.Bytes()
+	if path.Ext(name) == ".html" {
+		if body, err = decorate(body, failures); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(body)
+}
+
+// decorate adds the reload script and any failures to a page.
+func decorate(page []byte, failures []*tf_docs.ModuleFailure) ([]byte, error) {
+	html := string(page)
+	if len(failures) > 0 {
+		var buf bytes.Buffer
+		if err := failuresTemplate.Execute(&buf, failures); err != nil {
+			return nil, err
+		}
+		if i := strings.Index(html, "<main>"); i >= 0 {
+			i += len("<main>\n")
+			html = html[:i] + buf.String() + html[i:]
+		}
+	}
+	if i := strings.LastIndex(html, "</body>"); i >= 0 {
+		html = html[:i] + reloadScript + html[i:]
+	}
+	return []byte(html), nil
+}
+
+// serveEvents streams an event to the client every time the modules change, until it disconnects.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events := make(chan struct{}, 1)
+	s.clientsMu.Lock()
+	s.clients[events] = true
+	s.clientsMu.Unlock()
+	defer func() {
+		s.clientsMu.Lock()
+		delete(s.clients, events)
+		s.clientsMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-events:
+			if _, err := fmt.Fprint(w, "data: reload\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// reload tells every connected client to reload.
+func (s *Server) reload() {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	for events := range s.clients {
+		select {
+		case events <- struct{}{}:
+		default:
+			// A reload is already pending for this client.
+		}
+	}
+}
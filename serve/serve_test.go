@@ -0,0 +1,124 @@
+package serve
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/nathmclean/tf_docs/watch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	root := t.TempDir()
+	vpc := filepath.Join(root, "vpc")
+	assert.NoError(t, os.MkdirAll(vpc, 0755), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(vpc, "main.tf"), []byte("# vpc creates a network\n"), 0644), "")
+
+	modules, err := watch.Load(root, tf_docs.Options{Parser: tf_docs.ParseHCL2Files})
+	assert.NoError(t, err, "")
+	s, err := New(modules, nil)
+	assert.NoError(t, err, "")
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	cases := []struct {
+		Path        string
+		Status      int
+		ContentType string
+		Body        string
+	}{
+		{
+			Path:        "/",
+			Status:      http.StatusOK,
+			ContentType: "text/html; charset=utf-8",
+			Body:        reloadScript + "</body>",
+		},
+		{
+			Path:        "/vpc.html",
+			Status:      http.StatusOK,
+			ContentType: "text/html; charset=utf-8",
+			Body:        "vpc creates a network",
+		},
+		{
+			Path:        "/style.css",
+			Status:      http.StatusOK,
+			ContentType: "text/css; charset=utf-8",
+		},
+		{
+			Path:   "/missing.html",
+			Status: http.StatusNotFound,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("serve %v", i), func(t *testing.T) {
+			resp, err := http.Get(server.URL + c.Path)
+			assert.NoError(t, err, "")
+			defer resp.Body.Close()
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err, "")
+			assert.Equal(t, c.Status, resp.StatusCode, "")
+			if c.ContentType != "" {
+				assert.Equal(t, c.ContentType, resp.Header.Get("Content-Type"), "")
+			}
+			assert.Contains(t, string(body), c.Body, "")
+		})
+	}
+}
+
+func TestServerUpdate(t *testing.T) {
+	root := t.TempDir()
+	vpc := filepath.Join(root, "vpc")
+	assert.NoError(t, os.MkdirAll(vpc, 0755), "")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(vpc, "main.tf"), []byte("# vpc creates a network\n"), 0644), "")
+
+	modules, err := watch.Load(root, tf_docs.Options{Parser: tf_docs.ParseHCL2Files})
+	assert.NoError(t, err, "")
+	s, err := New(modules, nil)
+	assert.NoError(t, err, "")
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	events, err := http.Get(server.URL + EventsPath)
+	assert.NoError(t, err, "")
+	defer events.Body.Close()
+	assert.Equal(t, "text/event-stream", events.Header.Get("Content-Type"), "")
+	received := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(events.Body).ReadString('\n')
+		received <- line
+	}()
+
+	updated, err := s.Update([]string{root})
+	assert.NoError(t, err, "")
+	assert.Empty(t, updated, "a directory without a module should not change anything")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(vpc, "main.tf"), []byte(`variable "x" {`), 0644), "")
+	updated, err = s.Update([]string{vpc})
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{vpc}, updated, "")
+	select {
+	case line := <-received:
+		assert.Equal(t, "data: reload\n", line, "")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload was sent")
+	}
+
+	assert.Len(t, s.Failures(), 1, "")
+	resp, err := http.Get(server.URL + "/vpc.html")
+	assert.NoError(t, err, "")
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err, "")
+	assert.Contains(t, string(body), "vpc creates a network", "the last parsed version should still be served")
+	assert.Contains(t, string(body), `<div class="failures"`, "")
+	assert.Contains(t, string(body), "main.tf:1", "")
+}
@@ -0,0 +1,88 @@
+// search.js filters the search index of a tf_docs site as the search box is typed into. The index is
+// loaded by search-index.js into window.tfDocsSearchIndex, so searching needs no server.
+(function () {
+  "use strict";
+
+  var maxResults = 50;
+  var input = document.getElementById("search");
+  var list = document.getElementById("search-results");
+  var index = window.tfDocsSearchIndex || [];
+  if (!input || !list) {
+    return;
+  }
+
+  // matches reports whether every term appears within one of the texts.
+  function matches(terms, texts) {
+    var haystack = texts.join(" ").toLowerCase();
+    return terms.every(function (term) {
+      return haystack.indexOf(term) !== -1;
+    });
+  }
+
+  // search returns the modules, variables, outputs and resource types matching the query.
+  function search(query) {
+    var terms = query.toLowerCase().split(/\s+/).filter(Boolean);
+    var results = [];
+    if (terms.length === 0) {
+      return results;
+    }
+
+    index.forEach(function (module) {
+      if (matches(terms, [module.title, module.path || "", module.description || ""])) {
+        results.push({ kind: "module", name: module.title, module: module, href: module.page });
+      }
+      (module.variables || []).forEach(function (item) {
+        if (matches(terms, [item.name, item.description || ""])) {
+          results.push({ kind: "variable", name: item.name, module: module, href: module.page + "#" + item.anchor });
+        }
+      });
+      (module.outputs || []).forEach(function (item) {
+        if (matches(terms, [item.name, item.description || ""])) {
+          results.push({ kind: "output", name: item.name, module: module, href: module.page + "#" + item.anchor });
+        }
+      });
+      (module.resources || []).forEach(function (type) {
+        if (matches(terms, [type])) {
+          var data = type.indexOf("data.") === 0;
+          results.push({
+            kind: data ? "data source" : "resource",
+            name: data ? type.slice(5) : type,
+            module: module,
+            href: module.page + (data ? "#data-sources" : "#resources")
+          });
+        }
+      });
+    });
+
+    return results.slice(0, maxResults);
+  }
+
+  // render replaces the listed results.
+  function render(results) {
+    list.textContent = "";
+    results.forEach(function (result) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = encodeURI(result.href);
+      link.textContent = result.name;
+      var kind = document.createElement("div");
+      kind.className = "kind";
+      kind.textContent = result.kind === "module" ? "module " + (result.module.path || "/") : result.kind + " in " + result.module.title;
+      item.appendChild(link);
+      item.appendChild(kind);
+      list.appendChild(item);
+    });
+  }
+
+  input.addEventListener("input", function () {
+    render(search(input.value));
+  });
+  input.addEventListener("keydown", function (event) {
+    if (event.key === "Escape") {
+      input.value = "";
+      render([]);
+    } else if (event.key === "Enter" && list.firstChild) {
+      window.location.href = list.firstChild.querySelector("a").href;
+    }
+  });
+})();
@@ -0,0 +1,101 @@
+package site
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+const (
+	// SearchIndex is the name of the JSON search index page.
+	SearchIndex = "search.json"
+	// searchScript is the name of the page holding the search index as a script. Browsers do not let
+	// pages opened from disk fetch other files, so the search box loads the index through a script
+	// element instead of reading SearchIndex.
+	searchScript = "search-index.js"
+)
+
+// SearchEntry is the entry of a module within the search index.
+type SearchEntry struct {
+	Title       string        `json:"title"`
+	Page        string        `json:"page"`
+	Path        string        `json:"path,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Variables   []*SearchItem `json:"variables,omitempty"`
+	Outputs     []*SearchItem `json:"outputs,omitempty"`
+	// Resources are the types of the resources and data sources the module declares, sorted and
+	// without duplicates. Data sources are prefixed with "data.".
+	Resources []string `json:"resources,omitempty"`
+}
+
+// SearchItem is a variable or output within the search index. Anchor is the id of its row on the
+// module's page.
+type SearchItem struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Anchor      string `json:"anchor"`
+}
+
+// SearchIndex returns the search index of the site, an entry per module in navigation order.
+func (s *Site) SearchIndex() []*SearchEntry {
+	entries := []*SearchEntry{}
+	for _, g := range s.groups {
+		for _, module := range g.Modules {
+			entries = append(entries, searchEntry(module))
+		}
+	}
+	return entries
+}
+
+// searchEntry returns the search index entry of a module.
+func searchEntry(module *tf_docs.TFModule) *SearchEntry {
+	entry := &SearchEntry{
+		Title:       module.Title,
+		Page:        PageName(module),
+		Path:        module.Path,
+		Description: module.Description,
+	}
+	for _, variable := range module.Variables {
+		entry.Variables = append(entry.Variables, &SearchItem{Name: variable.Name, Description: variable.Description, Anchor: "var-" + variable.Name})
+	}
+	for _, output := range module.Outputs {
+		entry.Outputs = append(entry.Outputs, &SearchItem{Name: output.Name, Description: output.Description, Anchor: "output-" + output.Name})
+	}
+
+	types := map[string]bool{}
+	for _, resource := range module.Resources {
+		types[resource.Type] = true
+	}
+	for _, data := range module.DataSources {
+		types["data."+data.Type] = true
+	}
+	for t := range types {
+		entry.Resources = append(entry.Resources, t)
+	}
+	sort.Strings(entry.Resources)
+
+	return entry
+}
+
+// writeSearchIndex writes the search index as JSON.
+func (s *Site) writeSearchIndex(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s.SearchIndex())
+}
+
+// writeSearchScript writes the search index as a script assigning it to window.tfDocsSearchIndex.
+func (s *Site) writeSearchScript(w io.Writer) error {
+	if _, err := io.WriteString(w, "window.tfDocsSearchIndex = "); err != nil {
+		return err
+	}
+	body, err := json.Marshal(s.SearchIndex())
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, ";\n")
+	return err
+}
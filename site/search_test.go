@@ -0,0 +1,51 @@
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchIndex(t *testing.T) {
+	modules := testModules()
+	modules[0].Outputs = []*tf_docs.Output{{Name: "id", Description: "the VPC id"}}
+	modules[0].Resources = []*tf_docs.Resource{{Type: "aws_vpc", Name: "this"}, {Type: "aws_subnet", Name: "a"}, {Type: "aws_subnet", Name: "b"}}
+	modules[0].DataSources = []*tf_docs.DataSource{{Type: "aws_region", Name: "current"}}
+	s, err := New(modules)
+	assert.NoError(t, err, "")
+
+	assert.Equal(t, []*SearchEntry{
+		{Title: "app", Page: "app.html"},
+		{
+			Title:       "vpc",
+			Page:        "network_vpc.html",
+			Path:        "network",
+			Description: "vpc creates <networks>",
+			Variables:   []*SearchItem{{Name: "cidr", Anchor: "var-cidr"}},
+			Outputs:     []*SearchItem{{Name: "id", Description: "the VPC id", Anchor: "output-id"}},
+			Resources:   []string{"aws_subnet", "aws_vpc", "data.aws_region"},
+		},
+		{Title: "dns", Page: "network_dns.html", Path: "network"},
+	}, s.SearchIndex(), "")
+
+	var index bytes.Buffer
+	assert.NoError(t, s.WritePage(&index, SearchIndex), "")
+	var entries []*SearchEntry
+	assert.NoError(t, json.Unmarshal(index.Bytes(), &entries), "")
+	assert.Equal(t, s.SearchIndex(), entries, "")
+
+	var script bytes.Buffer
+	assert.NoError(t, s.WritePage(&script, "search-index.js"), "")
+	assert.True(t, strings.HasPrefix(script.String(), "window.tfDocsSearchIndex = [{"), script.String())
+	assert.True(t, strings.HasSuffix(script.String(), "}];\n"), script.String())
+
+	empty, err := New(nil)
+	assert.NoError(t, err, "")
+	index.Reset()
+	assert.NoError(t, empty.WritePage(&index, SearchIndex), "")
+	assert.Equal(t, "[]\n", index.String(), "")
+}
@@ -0,0 +1,226 @@
+// Package site generates a static HTML documentation site for the modules found by tf_docs: an index
+// of every module grouped by path, a page per module and a search index queried by a search box on
+// every page. Everything the pages need is bundled, so the site works offline.
+package site
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+//go:embed templates assets
+var files embed.FS
+
+// Index is the name of the index page.
+const Index = "index.html"
+
+// assets are the bundled files every site includes, by page name.
+var assets = map[string]string{
+	"search.js": "assets/search.js",
+	"style.css": "assets/style.css",
+}
+
+// generated are the pages every site includes that are built from its modules, by page name.
+var generated = map[string]func(*Site, io.Writer) error{
+	SearchIndex:  (*Site).writeSearchIndex,
+	searchScript: (*Site).writeSearchScript,
+}
+
+// Site is the documentation site of a set of modules.
+type Site struct {
+	modules []*tf_docs.TFModule
+	groups  []*group
+	byPage  map[string]*tf_docs.TFModule
+	tmpl    *template.Template
+}
+
+// group is the modules sharing a TFModule.Path.
+type group struct {
+	Path    string
+	Modules []*tf_docs.TFModule
+}
+
+// call is a module call along with the documented module it calls, if any.
+type call struct {
+	*tf_docs.Module
+	Target *tf_docs.TFModule
+}
+
+// pageData is what the page templates are executed with.
+type pageData struct {
+	Title  string
+	Groups []*group
+	// Current is the module the page documents, nil on the index page.
+	Current  *tf_docs.TFModule
+	Previous *tf_docs.TFModule
+	Next     *tf_docs.TFModule
+	Calls    []*call
+	CalledBy []*tf_docs.TFModule
+}
+
+// funcs are the helpers available to the page templates.
+var funcs = template.FuncMap{
+	"page": PageName,
+	"href": func(m *tf_docs.TFModule) string { return url.PathEscape(PageName(m)) },
+	"yesno": func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	},
+	"group": func(path string) string {
+		if path == "" {
+			return "/"
+		}
+		return path
+	},
+	"sortedKeys": func(m map[string]string) []string {
+		var keys []string
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	},
+}
+
+// New returns the site of modules, such as the result of FindAndParse.
+func New(modules []*tf_docs.TFModule) (*Site, error) {
+	tmpl, err := template.New("site").Funcs(funcs).ParseFS(files, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Site{modules: modules, byPage: map[string]*tf_docs.TFModule{}, tmpl: tmpl}
+	groups := map[string]*group{}
+	for _, module := range modules {
+		name := PageName(module)
+		if _, ok := s.byPage[name]; ok || name == Index || assets[name] != "" || generated[name] != nil {
+			return nil, fmt.Errorf("module %s: page %s is already used", module.Dir, name)
+		}
+		s.byPage[name] = module
+
+		g, ok := groups[module.Path]
+		if !ok {
+			g = &group{Path: module.Path}
+			groups[module.Path] = g
+			s.groups = append(s.groups, g)
+		}
+		g.Modules = append(g.Modules, module)
+	}
+	sort.SliceStable(s.groups, func(i, j int) bool { return s.groups[i].Path < s.groups[j].Path })
+
+	return s, nil
+}
+
+// PageName returns the name of the page documenting a module, built from its Link.
+func PageName(module *tf_docs.TFModule) string {
+	return module.Link + ".html"
+}
+
+// Pages returns the name of every page of the site: the index, the page of each module, then the
+// search index and the bundled assets.
+func (s *Site) Pages() []string {
+	pages := []string{Index}
+	for _, module := range s.modules {
+		pages = append(pages, PageName(module))
+	}
+	var names []string
+	for name := range assets {
+		names = append(names, name)
+	}
+	for name := range generated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append(pages, names...)
+}
+
+// WritePage writes the named page. It returns an error wrapping os.ErrNotExist if the site has no
+// such page.
+func (s *Site) WritePage(w io.Writer, name string) error {
+	if asset, ok := assets[name]; ok {
+		body, err := files.ReadFile(asset)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(body)
+		return err
+	}
+	if write, ok := generated[name]; ok {
+		return write(s, w)
+	}
+	if name == Index {
+		return s.tmpl.ExecuteTemplate(w, "index.html", &pageData{Title: "Modules", Groups: s.groups})
+	}
+	module, ok := s.byPage[name]
+	if !ok {
+		return fmt.Errorf("page %s: %w", name, os.ErrNotExist)
+	}
+	return s.tmpl.ExecuteTemplate(w, "module.html", s.modulePage(module))
+}
+
+// modulePage returns the data of a module's page: the modules before and after it in the
+// navigation, and the documented modules it calls and is called by.
+func (s *Site) modulePage(module *tf_docs.TFModule) *pageData {
+	data := &pageData{Title: module.Title, Groups: s.groups, Current: module}
+
+	var ordered []*tf_docs.TFModule
+	for _, g := range s.groups {
+		ordered = append(ordered, g.Modules...)
+	}
+	for i, m := range ordered {
+		if m != module {
+			continue
+		}
+		if i > 0 {
+			data.Previous = ordered[i-1]
+		}
+		if i < len(ordered)-1 {
+			data.Next = ordered[i+1]
+		}
+	}
+
+	for _, c := range module.Modules {
+		data.Calls = append(data.Calls, &call{Module: c, Target: tf_docs.ResolveLocalModule(s.modules, module, c)})
+	}
+	for _, caller := range s.modules {
+		for _, c := range caller.Modules {
+			if tf_docs.ResolveLocalModule(s.modules, caller, c) == module {
+				data.CalledBy = append(data.CalledBy, caller)
+				break
+			}
+		}
+	}
+
+	return data
+}
+
+// Write writes every page of the site into directory, creating it if needed.
+func (s *Site) Write(directory string) error {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+	for _, name := range s.Pages() {
+		f, err := os.Create(filepath.Join(directory, name))
+		if err != nil {
+			return err
+		}
+		if err := s.WritePage(f, name); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
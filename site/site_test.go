@@ -0,0 +1,99 @@
+package site
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func testModules() []*tf_docs.TFModule {
+	return []*tf_docs.TFModule{
+		{
+			Dir:         "root/network/vpc",
+			Path:        "network",
+			Title:       "vpc",
+			Link:        "network_vpc",
+			Description: "vpc creates <networks>",
+			Variables:   []*tf_docs.Variable{{Name: "cidr", Type: "string", Required: true}},
+		},
+		{
+			Dir:   "root/app",
+			Title: "app",
+			Link:  "app",
+			Modules: []*tf_docs.Module{
+				{Name: "vpc", Source: "../network/vpc", Inputs: map[string]string{"cidr": "var.cidr", "name": "\"app\""}},
+				{Name: "registry", Source: "terraform-aws-modules/vpc/aws", Version: "~> 2.0"},
+			},
+		},
+		{
+			Dir:   "root/network/dns",
+			Path:  "network",
+			Title: "dns",
+			Link:  "network_dns",
+		},
+	}
+}
+
+func TestPages(t *testing.T) {
+	s, err := New(testModules())
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{"index.html", "network_vpc.html", "app.html", "network_dns.html", "search-index.js", "search.js", "search.json", "style.css"}, s.Pages(), "")
+
+	_, err = New([]*tf_docs.TFModule{{Link: "a"}, {Link: "a"}})
+	assert.Error(t, err, "two modules with the same page should fail")
+	_, err = New([]*tf_docs.TFModule{{Link: "index"}})
+	assert.Error(t, err, "a module named index should fail")
+}
+
+func TestWritePage(t *testing.T) {
+	s, err := New(testModules())
+	assert.NoError(t, err, "")
+
+	var index bytes.Buffer
+	assert.NoError(t, s.WritePage(&index, Index), "")
+	assert.Contains(t, index.String(), `<link rel="stylesheet" href="style.css">`, "")
+	assert.Contains(t, index.String(), "<script src=\"search-index.js\"></script>\n<script src=\"search.js\"></script>", "")
+	root := strings.Index(index.String(), "<h2>/</h2>\n<table>")
+	network := strings.Index(index.String(), "<h2>network</h2>\n<table>")
+	assert.True(t, root > 0 && network > root, "modules should be grouped by path")
+	assert.Contains(t, index.String(), `<td><a href="network_vpc.html">vpc</a></td><td>vpc creates &lt;networks&gt;</td>`, "")
+
+	var app bytes.Buffer
+	assert.NoError(t, s.WritePage(&app, "app.html"), "")
+	assert.Contains(t, app.String(), `<li><a href="app.html" class="current">app</a></li>`, "")
+	assert.Contains(t, app.String(), `<td><a href="network_vpc.html">../network/vpc</a></td>`, "calls to documented modules should link to them")
+	assert.Contains(t, app.String(), `<td><code>terraform-aws-modules/vpc/aws</code></td><td><code>~&gt; 2.0</code></td>`, "")
+	assert.Contains(t, app.String(), `<code>cidr = var.cidr</code><br><code>name = &#34;app&#34;</code>`, "")
+	assert.Contains(t, app.String(), `<a class="next" href="network_vpc.html">vpc &rarr;</a>`, "")
+	assert.NotContains(t, app.String(), `class="previous"`, "app is the first module in the navigation")
+
+	var vpc bytes.Buffer
+	assert.NoError(t, s.WritePage(&vpc, "network_vpc.html"), "")
+	assert.Contains(t, vpc.String(), "<h2 id=\"called-by\">Called By</h2>\n<ul>\n<li><a href=\"app.html\">app</a></li>", "")
+	assert.Contains(t, vpc.String(), `<tr id="var-cidr"><td>cidr</td><td><code>string</code></td><td></td><td>yes</td>`, "")
+
+	for _, page := range []*bytes.Buffer{&index, &app, &vpc} {
+		assert.NotRegexp(t, `(src|href)="(https?:)?//`, page.String(), "pages should not load anything remote")
+	}
+
+	assert.ErrorIs(t, s.WritePage(&bytes.Buffer{}, "missing.html"), os.ErrNotExist, "")
+}
+
+func TestWrite(t *testing.T) {
+	s, err := New(testModules())
+	assert.NoError(t, err, "")
+
+	dir := filepath.Join(t.TempDir(), "site")
+	assert.NoError(t, s.Write(dir), "")
+	for _, name := range s.Pages() {
+		body, err := ioutil.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err, "")
+		assert.NotEmpty(t, body, name)
+	}
+}
@@ -0,0 +1,33 @@
+package tf_docs
+
+import "sort"
+
+// SortByName sorts the variables, outputs, resources, data sources, module calls, providers and
+// locals of a module by name, rather than the order they are declared in. Resources and data sources
+// are sorted by type, then name, and providers by name, then alias.
+func SortByName(module *TFModule) {
+	sort.SliceStable(module.Variables, func(i, j int) bool {
+		return module.Variables[i].Name < module.Variables[j].Name
+	})
+	sort.SliceStable(module.Outputs, func(i, j int) bool {
+		return module.Outputs[i].Name < module.Outputs[j].Name
+	})
+	sort.SliceStable(module.Resources, func(i, j int) bool {
+		a, b := module.Resources[i], module.Resources[j]
+		return a.Type < b.Type || a.Type == b.Type && a.Name < b.Name
+	})
+	sort.SliceStable(module.DataSources, func(i, j int) bool {
+		a, b := module.DataSources[i], module.DataSources[j]
+		return a.Type < b.Type || a.Type == b.Type && a.Name < b.Name
+	})
+	sort.SliceStable(module.Modules, func(i, j int) bool {
+		return module.Modules[i].Name < module.Modules[j].Name
+	})
+	sort.SliceStable(module.Providers, func(i, j int) bool {
+		a, b := module.Providers[i], module.Providers[j]
+		return a.Name < b.Name || a.Name == b.Name && a.Alias < b.Alias
+	})
+	sort.SliceStable(module.Locals, func(i, j int) bool {
+		return module.Locals[i].Name < module.Locals[j].Name
+	})
+}
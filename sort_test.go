@@ -0,0 +1,30 @@
+package tf_docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortByName(t *testing.T) {
+	module := &TFModule{
+		Variables:   []*Variable{{Name: "zone"}, {Name: "cidr"}},
+		Outputs:     []*Output{{Name: "id"}, {Name: "arn"}},
+		Resources:   []*Resource{{Type: "aws_vpc", Name: "main"}, {Type: "aws_subnet", Name: "b"}, {Type: "aws_subnet", Name: "a"}},
+		DataSources: []*DataSource{{Type: "aws_region", Name: "current"}, {Type: "aws_ami", Name: "ubuntu"}},
+		Modules:     []*Module{{Name: "vpc"}, {Name: "dns"}},
+		Providers:   []*Provider{{Name: "aws", Alias: "west"}, {Name: "aws"}, {Name: "archive"}},
+		Locals:      []*Local{{Name: "tags"}, {Name: "name"}},
+	}
+	SortByName(module)
+
+	assert.Equal(t, &TFModule{
+		Variables:   []*Variable{{Name: "cidr"}, {Name: "zone"}},
+		Outputs:     []*Output{{Name: "arn"}, {Name: "id"}},
+		Resources:   []*Resource{{Type: "aws_subnet", Name: "a"}, {Type: "aws_subnet", Name: "b"}, {Type: "aws_vpc", Name: "main"}},
+		DataSources: []*DataSource{{Type: "aws_ami", Name: "ubuntu"}, {Type: "aws_region", Name: "current"}},
+		Modules:     []*Module{{Name: "dns"}, {Name: "vpc"}},
+		Providers:   []*Provider{{Name: "archive"}, {Name: "aws"}, {Name: "aws", Alias: "west"}},
+		Locals:      []*Local{{Name: "name"}, {Name: "tags"}},
+	}, module, "")
+}
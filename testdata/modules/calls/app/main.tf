@@ -0,0 +1,25 @@
+# app runs the application
+
+# the network the application runs in
+module "vpc" {
+  source = "../vpc"
+
+  name  = "app"
+  zones = ["a", "b"]
+}
+
+# the registry module is not checked
+module "registry" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 2.0"
+}
+
+output "vpc_id" {
+  description = "the id of the VPC"
+  value       = module.vpc.id
+}
+
+output "vpc_arn" {
+  description = "the ARN of the VPC"
+  value       = module.vpc.arn
+}
@@ -0,0 +1,25 @@
+# vpc creates a network
+
+variable "cidr" {
+  type        = string
+  description = "the CIDR block of the VPC"
+}
+
+variable "name" {
+  type        = string
+  description = "the name of the VPC"
+  default     = "main"
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = var.cidr
+
+  tags = {
+    Name = var.name
+  }
+}
+
+output "id" {
+  description = "the id of the VPC"
+  value       = aws_vpc.main.id
+}
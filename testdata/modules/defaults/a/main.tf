@@ -0,0 +1,7 @@
+# a calls b without the variable that defaults to an empty string
+
+module "b" {
+  source = "../b"
+
+  x = "x"
+}
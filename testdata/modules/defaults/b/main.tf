@@ -0,0 +1,17 @@
+# b has a variable that defaults to an empty string
+
+variable "x" {
+  type        = string
+  description = "a required variable"
+}
+
+variable "y" {
+  type        = string
+  description = "an optional variable defaulting to an empty string"
+  default     = ""
+}
+
+output "xy" {
+  description = "x and y"
+  value       = "${var.x}${var.y}"
+}
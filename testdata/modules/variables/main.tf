@@ -0,0 +1,29 @@
+# variables shows how variables are used
+
+variable "region" {
+  type        = string
+  description = "the region to deploy to"
+}
+
+variable "name" {
+  type        = string
+  description = "the name of the bucket"
+}
+
+variable "unused" {
+  type        = string
+  description = "a variable nothing uses"
+}
+
+provider "aws" {
+  region = var.region
+}
+
+resource "aws_s3_bucket" "main" {
+  bucket = "${var.name}-${var.suffix}"
+}
+
+output "arn" {
+  description = "the ARN of the bucket"
+  value       = aws_s3_bucket.main.arn
+}
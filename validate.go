@@ -0,0 +1,162 @@
+package tf_docs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finding is a problem found within a module, such as a module call missing a required variable.
+type Finding struct {
+	// Dir is the directory of the module the finding is in. Range.Filename is relative to it.
+	Dir     string
+	Range   Range
+	Message string
+}
+
+func (f *Finding) String() string {
+	filename := f.Range.Filename
+	if f.Dir != "" {
+		filename = filepath.Join(f.Dir, filename)
+	}
+	return fmt.Sprintf("%s: %s", location(filename, f.Range.Start.Line, f.Range.Start.Column), f.Message)
+}
+
+// IsLocalSource reports whether a module source is a local path, such as "./vpc" or "../", rather
+// than a registry address, URL or git repository.
+func IsLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") || source == "." || source == ".."
+}
+
+// ResolveLocalModule returns the module, among modules, that call refers to when it is made from the
+// caller module. It returns nil if the call's source is not local or is not one of the modules.
+func ResolveLocalModule(modules []*TFModule, caller *TFModule, call *Module) *TFModule {
+	if !IsLocalSource(call.Source) {
+		return nil
+	}
+
+	target := filepath.Clean(filepath.Join(caller.Dir, call.Source))
+	for _, module := range modules {
+		if filepath.Clean(module.Dir) == target {
+			return module
+		}
+	}
+
+	return nil
+}
+
+// ValidateModuleCalls checks every call to a local module against the variables and outputs of the
+// module it calls. It reports required variables that are not passed, arguments that are not
+// variables of the called module, and references to outputs the called module does not declare.
+func ValidateModuleCalls(modules []*TFModule) []*Finding {
+	var findings []*Finding
+
+	for _, caller := range modules {
+		calls := map[string]*TFModule{}
+		for _, call := range caller.Modules {
+			target := ResolveLocalModule(modules, caller, call)
+			if target == nil {
+				continue
+			}
+			calls[call.Name] = target
+			findings = append(findings, validateInputs(caller, call, target)...)
+		}
+
+		for _, reference := range moduleReferences(caller) {
+			parts := strings.Split(reference.Name, ".")
+			if len(parts) < 3 || parts[0] != MODULE {
+				continue
+			}
+			target, ok := calls[parts[1]]
+			if !ok || hasOutput(target, parts[2]) {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Dir:     caller.Dir,
+				Range:   reference.Range,
+				Message: fmt.Sprintf("module %q (%s) has no output %q", parts[1], target.Dir, parts[2]),
+			})
+		}
+	}
+
+	return findings
+}
+
+// ValidateVariables checks the use of variables within each module. It reports variables that are
+// declared but never referenced, and references to variables a module does not declare.
+func ValidateVariables(modules []*TFModule) []*Finding {
+	var findings []*Finding
+
+	for _, module := range modules {
+		declared := map[string]bool{}
+		for _, variable := range module.Variables {
+			declared[variable.Name] = true
+			if len(variable.UsedBy) == 0 {
+				findings = append(findings, &Finding{
+					Dir:     module.Dir,
+					Range:   variable.Range,
+					Message: fmt.Sprintf("variable %q is declared but not used", variable.Name),
+				})
+			}
+		}
+
+		for _, reference := range moduleReferences(module) {
+			parts := strings.Split(reference.Name, ".")
+			if len(parts) < 2 || parts[0] != "var" || declared[parts[1]] {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Dir:     module.Dir,
+				Range:   reference.Range,
+				Message: fmt.Sprintf("reference to undeclared variable %q", parts[1]),
+			})
+		}
+	}
+
+	return findings
+}
+
+// validateInputs checks the arguments of a call against the variables of the module it calls.
+func validateInputs(caller *TFModule, call *Module, target *TFModule) []*Finding {
+	var findings []*Finding
+
+	variables := map[string]*Variable{}
+	for _, variable := range target.Variables {
+		variables[variable.Name] = variable
+		if _, ok := call.Inputs[variable.Name]; variable.Required && !ok {
+			findings = append(findings, &Finding{
+				Dir:     caller.Dir,
+				Range:   call.Range,
+				Message: fmt.Sprintf("module %q (%s) requires variable %q", call.Name, target.Dir, variable.Name),
+			})
+		}
+	}
+
+	var inputs []string
+	for name := range call.Inputs {
+		inputs = append(inputs, name)
+	}
+	sort.Strings(inputs)
+	for _, name := range inputs {
+		if _, ok := variables[name]; !ok {
+			findings = append(findings, &Finding{
+				Dir:     caller.Dir,
+				Range:   call.Range,
+				Message: fmt.Sprintf("module %q (%s) has no variable %q", call.Name, target.Dir, name),
+			})
+		}
+	}
+
+	return findings
+}
+
+// hasOutput reports whether a module declares the named output.
+func hasOutput(module *TFModule, name string) bool {
+	for _, output := range module.Outputs {
+		if output.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package tf_docs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLocalSource(t *testing.T) {
+	cases := []struct {
+		Source string
+		Local  bool
+	}{
+		{Source: "./vpc", Local: true},
+		{Source: "../", Local: true},
+		{Source: "..", Local: true},
+		{Source: "terraform-aws-modules/vpc/aws", Local: false},
+		{Source: "git::https://example.com/vpc.git", Local: false},
+		{Source: "github.com/hashicorp/example", Local: false},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("IsLocalSource %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Local, IsLocalSource(c.Source), "")
+		})
+	}
+}
+
+func TestResolveLocalModule(t *testing.T) {
+	vpc := &TFModule{Dir: "root/network/vpc"}
+	app := &TFModule{Dir: "root/app"}
+	modules := []*TFModule{vpc, app}
+
+	cases := []struct {
+		Source string
+		Result *TFModule
+	}{
+		{Source: "../network/vpc", Result: vpc},
+		{Source: "../network/vpc/", Result: vpc},
+		{Source: "./vpc", Result: nil},
+		{Source: "terraform-aws-modules/vpc/aws", Result: nil},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("ResolveLocalModule %v", i), func(t *testing.T) {
+			assert.Equal(t, c.Result, ResolveLocalModule(modules, app, &Module{Source: c.Source}), "")
+		})
+	}
+}
+
+func TestValidateModuleCalls(t *testing.T) {
+	result, err := FindAndParseWithOptions("./testdata/modules/calls", Options{Parser: ParseHCL2Files})
+	assert.NoError(t, err, "")
+
+	var messages []string
+	for _, finding := range ValidateModuleCalls(result.Modules) {
+		messages = append(messages, finding.String())
+	}
+	assert.Equal(t, []string{
+		`testdata/modules/calls/app/main.tf:4:1: module "vpc" (./testdata/modules/calls/vpc) requires variable "cidr"`,
+		`testdata/modules/calls/app/main.tf:4:1: module "vpc" (./testdata/modules/calls/vpc) has no variable "zones"`,
+		`testdata/modules/calls/app/main.tf:24:17: module "vpc" (./testdata/modules/calls/vpc) has no output "arn"`,
+	}, messages, "")
+}
+
+func TestValidateModuleCallsEmptyDefault(t *testing.T) {
+	result, err := FindAndParseWithOptions("./testdata/modules/defaults", Options{Parser: ParseHCL2Files})
+	assert.NoError(t, err, "")
+
+	assert.Empty(t, ValidateModuleCalls(result.Modules), "a variable defaulting to an empty string is not required")
+	assert.Empty(t, ValidateVariables(result.Modules), "")
+}
+
+func TestValidateVariables(t *testing.T) {
+	result, err := FindAndParseWithOptions("./testdata/modules/variables", Options{Parser: ParseHCL2Files})
+	assert.NoError(t, err, "")
+
+	module := result.Modules[0]
+	assert.Equal(t, []string{"provider.aws"}, module.Variables[0].UsedBy, "")
+	assert.Equal(t, []string{"aws_s3_bucket.main"}, module.Variables[1].UsedBy, "")
+	assert.Equal(t, []string{"aws_s3_bucket.main"}, module.Outputs[0].Exposes, "")
+
+	var messages []string
+	for _, finding := range ValidateVariables(result.Modules) {
+		messages = append(messages, finding.String())
+	}
+	assert.Equal(t, []string{
+		`testdata/modules/variables/main.tf:13:1: variable "unused" is declared but not used`,
+		`testdata/modules/variables/main.tf:23:27: reference to undeclared variable "suffix"`,
+	}, messages, "")
+}
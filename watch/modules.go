@@ -0,0 +1,128 @@
+package watch
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/nathmclean/tf_docs"
+)
+
+// Modules are the modules within a root directory, kept up to date as its directories change.
+// Modules is not safe for concurrent use.
+type Modules struct {
+	root string
+	opts tf_docs.Options
+	// dirs are the module directories within root, in the order FindModuleDirs returns them.
+	dirs []string
+	// modules and failures are keyed by the cleaned module directory.
+	modules  map[string]*tf_docs.TFModule
+	failures map[string]error
+}
+
+// Load finds and parses every module within root using opts. Modules that cannot be parsed are
+// recorded as failures rather than returned as errors, as if opts.ContinueOnError were set, so that
+// they can be fixed while watching.
+func Load(root string, opts tf_docs.Options) (*Modules, error) {
+	opts.ContinueOnError = true
+	result, err := tf_docs.FindAndParseWithOptions(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := tf_docs.FindModuleDirs(root, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Modules{
+		root:     root,
+		opts:     opts,
+		dirs:     dirs,
+		modules:  map[string]*tf_docs.TFModule{},
+		failures: map[string]error{},
+	}
+	for _, module := range result.Modules {
+		m.modules[filepath.Clean(module.Dir)] = module
+	}
+	for _, failure := range result.Failures {
+		m.failures[filepath.Clean(failure.Dir)] = failure.Err
+	}
+	return m, nil
+}
+
+// Update re-parses the modules in the changed directories, parses any module directories that have
+// been created and forgets those that have been removed. It returns the cleaned directories of the
+// modules that were parsed or removed, sorted. A module that fails to parse keeps its last parsed
+// version, if any, until it is fixed.
+func (m *Modules) Update(changed []string) ([]string, error) {
+	dirs, err := tf_docs.FindModuleDirs(m.root, m.opts)
+	if err != nil {
+		return nil, err
+	}
+	isChanged := map[string]bool{}
+	for _, dir := range changed {
+		isChanged[filepath.Clean(dir)] = true
+	}
+
+	var updated []string
+	found := map[string]bool{}
+	for _, dir := range dirs {
+		key := filepath.Clean(dir)
+		found[key] = true
+		_, parsed := m.modules[key]
+		_, failed := m.failures[key]
+		if (parsed || failed) && !isChanged[key] {
+			continue
+		}
+
+		updated = append(updated, key)
+		module, err := tf_docs.ParseModuleDir(m.root, dir, m.opts)
+		if err != nil {
+			m.failures[key] = err
+			continue
+		}
+		delete(m.failures, key)
+		m.modules[key] = module
+	}
+
+	removed := map[string]bool{}
+	for key := range m.modules {
+		removed[key] = !found[key]
+	}
+	for key := range m.failures {
+		removed[key] = !found[key]
+	}
+	for key, ok := range removed {
+		if !ok {
+			continue
+		}
+		updated = append(updated, key)
+		delete(m.modules, key)
+		delete(m.failures, key)
+	}
+	m.dirs = dirs
+
+	sort.Strings(updated)
+	return updated, nil
+}
+
+// Modules returns the parsed modules, in the order FindAndParse returns them.
+func (m *Modules) Modules() []*tf_docs.TFModule {
+	var modules []*tf_docs.TFModule
+	for _, dir := range m.dirs {
+		if module, ok := m.modules[filepath.Clean(dir)]; ok {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// Failures returns the modules that failed to parse the last time they were parsed.
+func (m *Modules) Failures() []*tf_docs.ModuleFailure {
+	var failures []*tf_docs.ModuleFailure
+	for _, dir := range m.dirs {
+		if err, ok := m.failures[filepath.Clean(dir)]; ok {
+			failures = append(failures, &tf_docs.ModuleFailure{Dir: dir, Err: err})
+		}
+	}
+	return failures
+}
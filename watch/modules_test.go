@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nathmclean/tf_docs"
+	"github.com/stretchr/testify/assert"
+)
+
+func titles(modules []*tf_docs.TFModule) []string {
+	var titles []string
+	for _, module := range modules {
+		titles = append(titles, module.Title)
+	}
+	return titles
+}
+
+func TestModules(t *testing.T) {
+	root := t.TempDir()
+	write := func(dir, body string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755), "")
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(root, dir, "main.tf"), []byte(body), 0644), "")
+	}
+	write("app", "# app runs the application\n")
+	write("vpc", "# vpc creates a network\n")
+	app, vpc, dns := filepath.Join(root, "app"), filepath.Join(root, "vpc"), filepath.Join(root, "dns")
+
+	m, err := Load(root, tf_docs.Options{Parser: tf_docs.ParseHCL2Files})
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{"app", "vpc"}, titles(m.Modules()), "")
+	before := m.Modules()
+
+	write("vpc", "# vpc creates a VPC\n")
+	updated, err := m.Update([]string{vpc})
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{vpc}, updated, "")
+	assert.Equal(t, "vpc creates a VPC", m.Modules()[1].Description, "")
+	assert.Same(t, before[0], m.Modules()[0], "modules that did not change should not be parsed again")
+
+	write("dns", "# dns creates zones\n")
+	updated, err = m.Update(nil)
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{dns}, updated, "new module directories should be parsed")
+	assert.Equal(t, []string{"app", "dns", "vpc"}, titles(m.Modules()), "")
+
+	write("app", `variable "x" {`)
+	updated, err = m.Update([]string{app})
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{app}, updated, "")
+	assert.Equal(t, []string{"app", "dns", "vpc"}, titles(m.Modules()), "a module that fails to parse should keep its last version")
+	if assert.Len(t, m.Failures(), 1, "") {
+		assert.Equal(t, app, m.Failures()[0].Dir, "")
+	}
+
+	write("app", "# app runs the application\n")
+	_, err = m.Update([]string{app})
+	assert.NoError(t, err, "")
+	assert.Empty(t, m.Failures(), "")
+
+	assert.NoError(t, os.RemoveAll(vpc), "")
+	updated, err = m.Update([]string{vpc})
+	assert.NoError(t, err, "")
+	assert.Equal(t, []string{vpc}, updated, "")
+	assert.Equal(t, []string{"app", "dns"}, titles(m.Modules()), "")
+
+	_, err = Load(filepath.Join(root, "missing"), tf_docs.Options{})
+	assert.Error(t, err, "")
+}
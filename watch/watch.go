@@ -0,0 +1,143 @@
+// Package watch watches a directory of Terraform modules for changes, reporting the directories whose
+// .tf files change, and keeps the modules within it up to date by re-parsing only those directories.
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nathmclean/tf_docs"
+)
+
+// DefaultDelay is how long a Watcher waits for changes to stop before reporting them.
+const DefaultDelay = 200 * time.Millisecond
+
+// Watcher watches every directory within a root directory, including directories created after it
+// starts, for changes to .tf files, or the files of its conventions. Hidden directories and
+// .terraform are not watched.
+type Watcher struct {
+	conventions *tf_docs.Conventions
+	delay       time.Duration
+	watcher     *fsnotify.Watcher
+	// dirs are the watched directories.
+	dirs map[string]bool
+}
+
+// New returns a Watcher of the module files, as decided by conventions, within root. Changes are
+// reported once none have been made for delay.
+func New(root string, conventions *tf_docs.Conventions, delay time.Duration) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{conventions: conventions, delay: delay, watcher: watcher, dirs: map[string]bool{}}
+	if err := w.add(root); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// add watches directory and every directory within it but those skipped when searching for modules,
+// as reported by tf_docs.SkipDir.
+func (w *Watcher) add(directory string) error {
+	return filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Directories removed while walking are reported by their parent's watch.
+			if os.IsNotExist(err) && path != directory {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != directory && tf_docs.SkipDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return err
+		}
+		w.dirs[filepath.Clean(path)] = true
+		return nil
+	})
+}
+
+// Run calls changed with the directories that changed, sorted, whenever a module file is created,
+// written, removed or renamed, or a directory is created, removed or renamed. Changes are batched
+// until none have been made for the Watcher's delay. Errors watching, such as the queue of events
+// overflowing, are passed to failed, if set, and watching carries on. Run returns when ctx is
+// cancelled or the Watcher is closed.
+func (w *Watcher) Run(ctx context.Context, changed func(dirs []string), failed func(err error)) error {
+	pending := map[string]bool{}
+	timer := time.NewTimer(w.delay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			if failed != nil {
+				failed(err)
+			}
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			dir := w.changedDir(event)
+			if dir == "" {
+				continue
+			}
+			pending[dir] = true
+			timer.Reset(w.delay)
+		case <-timer.C:
+			var dirs []string
+			for dir := range pending {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			pending = map[string]bool{}
+			changed(dirs)
+		}
+	}
+}
+
+// changedDir returns the directory changed by event, or "" if the event is not one Run reports.
+// Directories created are watched.
+func (w *Watcher) changedDir(event fsnotify.Event) string {
+	name := filepath.Clean(event.Name)
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(name); err == nil && info.IsDir() {
+			if tf_docs.SkipDir(info.Name()) {
+				return ""
+			}
+			// A directory that cannot be watched, such as one already removed, is skipped.
+			w.add(name)
+			return name
+		}
+	}
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		if w.dirs[name] {
+			delete(w.dirs, name)
+			return name
+		}
+	}
+	if w.conventions.IsModuleFile(name) && event.Op&^fsnotify.Chmod != 0 {
+		return filepath.Dir(name)
+	}
+	return ""
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
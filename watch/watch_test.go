@@ -0,0 +1,148 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher(t *testing.T) {
+	root := t.TempDir()
+	vpc := filepath.Join(root, "vpc")
+	assert.NoError(t, os.MkdirAll(vpc, 0755), "")
+	downloaded := filepath.Join(vpc, ".terraform", "modules", "subnet")
+	assert.NoError(t, os.MkdirAll(downloaded, 0755), "")
+
+	w, err := New(root, nil, 50*time.Millisecond)
+	assert.NoError(t, err, "")
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches := make(chan []string)
+	go w.Run(ctx, func(dirs []string) {
+		select {
+		case batches <- dirs:
+		case <-ctx.Done():
+		}
+	}, nil)
+
+	dns := filepath.Join(root, "network", "dns")
+	cases := []struct {
+		Change    func() error
+		Changed   []string
+		Unchanged []string
+	}{
+		{
+			Change: func() error {
+				return ioutil.WriteFile(filepath.Join(vpc, "main.tf"), []byte(`variable "cidr" {}`), 0644)
+			},
+			Changed: []string{vpc},
+		},
+		{
+			Change: func() error {
+				if err := ioutil.WriteFile(filepath.Join(vpc, "README.md"), []byte("# vpc\n"), 0644); err != nil {
+					return err
+				}
+				return ioutil.WriteFile(filepath.Join(root, "main.tf"), []byte(""), 0644)
+			},
+			Changed: []string{root},
+		},
+		{
+			Change: func() error {
+				return os.MkdirAll(dns, 0755)
+			},
+			Changed: []string{filepath.Join(root, "network")},
+		},
+		{
+			Change: func() error {
+				return ioutil.WriteFile(filepath.Join(dns, "main.tf"), []byte(""), 0644)
+			},
+			Changed: []string{dns},
+		},
+		{
+			Change: func() error {
+				if err := ioutil.WriteFile(filepath.Join(downloaded, "main.tf"), []byte(""), 0644); err != nil {
+					return err
+				}
+				return ioutil.WriteFile(filepath.Join(vpc, "main.tf"), []byte(""), 0644)
+			},
+			Changed:   []string{vpc},
+			Unchanged: []string{downloaded},
+		},
+		{
+			Change: func() error {
+				if err := os.MkdirAll(filepath.Join(root, ".git", "refs"), 0755); err != nil {
+					return err
+				}
+				return ioutil.WriteFile(filepath.Join(dns, "outputs.tf"), []byte(""), 0644)
+			},
+			Changed:   []string{dns},
+			Unchanged: []string{filepath.Join(root, ".git")},
+		},
+		{
+			Change: func() error {
+				return os.RemoveAll(vpc)
+			},
+			Changed: []string{vpc},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("watch %v", i), func(t *testing.T) {
+			assert.NoError(t, c.Change(), "")
+			select {
+			case dirs := <-batches:
+				assert.Subset(t, dirs, c.Changed, "")
+				for _, dir := range c.Unchanged {
+					assert.NotContains(t, dirs, dir, "")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no changes were reported")
+			}
+		})
+	}
+}
+
+func TestWatcherErrors(t *testing.T) {
+	root := t.TempDir()
+	w, err := New(root, nil, 50*time.Millisecond)
+	assert.NoError(t, err, "")
+
+	batches := make(chan []string, 1)
+	failures := make(chan error, 1)
+	done := make(chan error)
+	go func() {
+		done <- w.Run(context.Background(), func(dirs []string) {
+			batches <- dirs
+		}, func(err error) {
+			failures <- err
+		})
+	}()
+
+	w.watcher.Errors <- errors.New("queue overflow")
+	assert.EqualError(t, <-failures, "queue overflow", "")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "main.tf"), []byte(""), 0644), "")
+	select {
+	case dirs := <-batches:
+		assert.Equal(t, []string{root}, dirs, "")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes were reported after an error")
+	}
+
+	assert.NoError(t, w.Close(), "")
+	select {
+	case err := <-done:
+		assert.NoError(t, err, "")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return when the Watcher was closed")
+	}
+}